@@ -0,0 +1,178 @@
+package bunnify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// EventFormat specifies how events are encoded when published.
+type EventFormat string
+
+const (
+	// EventFormatBunnify wraps the payload in the bunnify envelope with the metadata.
+	EventFormatBunnify EventFormat = "bunnify"
+	// EventFormatCloudEventsBinary follows the CloudEvents AMQP binding in binary mode,
+	// the attributes are sent as headers and the payload is the whole body.
+	EventFormatCloudEventsBinary EventFormat = "cloudevents-binary"
+	// EventFormatCloudEventsStructured follows the CloudEvents AMQP binding in structured mode,
+	// the body is the JSON representation of the CloudEvent including the attributes.
+	EventFormatCloudEventsStructured EventFormat = "cloudevents-structured"
+)
+
+const (
+	cloudEventsSpecVersion   = "1.0"
+	cloudEventsContentType   = "application/cloudevents+json"
+	cloudEventsHeaderPrefix  = "cloudEvents:"
+	cloudEventsDefaultSource = "bunnify"
+	jsonContentType          = "application/json"
+)
+
+// cloudEvent is the JSON representation of a CloudEvent. The correlation id is sent as an extension.
+type cloudEvent struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Subject         string          `json:"subject,omitempty"`
+	Time            time.Time       `json:"time,omitzero"`
+	DataContentType string          `json:"datacontenttype,omitempty"`
+	CorrelationID   string          `json:"correlationid,omitempty"`
+	Data            json.RawMessage `json:"data,omitempty"`
+}
+
+// newCloudEvent maps the metadata of the event onto the CloudEvent attributes.
+// If the event does not specify a type, the routing key is used instead.
+func newCloudEvent(routingKey string, event PublishableEvent) (cloudEvent, error) {
+	data, err := json.Marshal(event.Payload)
+	if err != nil {
+		return cloudEvent{}, err
+	}
+
+	ce := cloudEvent{
+		SpecVersion:     cloudEventsSpecVersion,
+		ID:              event.ID,
+		Source:          event.Source,
+		Type:            event.Type,
+		Subject:         event.Subject,
+		Time:            event.Timestamp,
+		DataContentType: jsonContentType,
+		CorrelationID:   event.CorrelationID,
+		Data:            data,
+	}
+
+	if ce.Source == "" {
+		ce.Source = cloudEventsDefaultSource
+	}
+	if ce.Type == "" {
+		ce.Type = routingKey
+	}
+
+	return ce, nil
+}
+
+// binaryHeaders returns the attributes of the CloudEvent as headers for the binary mode.
+// The data content type is sent as the content type property instead.
+func (ce cloudEvent) binaryHeaders() amqp.Table {
+	headers := amqp.Table{
+		cloudEventsHeaderPrefix + "specversion": ce.SpecVersion,
+		cloudEventsHeaderPrefix + "id":          ce.ID,
+		cloudEventsHeaderPrefix + "source":      ce.Source,
+		cloudEventsHeaderPrefix + "type":        ce.Type,
+	}
+	if ce.Subject != "" {
+		headers[cloudEventsHeaderPrefix+"subject"] = ce.Subject
+	}
+	if !ce.Time.IsZero() {
+		headers[cloudEventsHeaderPrefix+"time"] = ce.Time.UTC().Format(time.RFC3339Nano)
+	}
+	if ce.CorrelationID != "" {
+		headers[cloudEventsHeaderPrefix+"correlationid"] = ce.CorrelationID
+	}
+	return headers
+}
+
+// isCloudEvent returns true if the delivery is a CloudEvent either in structured or binary mode.
+func isCloudEvent(delivery amqp.Delivery) bool {
+	if strings.HasPrefix(delivery.ContentType, cloudEventsContentType) {
+		return true
+	}
+	_, ok := delivery.Headers[cloudEventsHeaderPrefix+"specversion"]
+	return ok
+}
+
+// decodeCloudEvent returns the event contained in a CloudEvent delivery.
+func decodeCloudEvent(delivery amqp.Delivery, deliveryInfo DeliveryInfo) (unmarshalEvent, error) {
+	uevt := unmarshalEvent{DeliveryInfo: deliveryInfo}
+
+	var ce cloudEvent
+	if strings.HasPrefix(delivery.ContentType, cloudEventsContentType) {
+		if err := json.Unmarshal(delivery.Body, &ce); err != nil {
+			return uevt, err
+		}
+	} else {
+		var err error
+		if ce, err = cloudEventFromHeaders(delivery.Headers); err != nil {
+			return uevt, err
+		}
+		ce.Data = delivery.Body
+	}
+
+	if ce.SpecVersion != cloudEventsSpecVersion {
+		return uevt, fmt.Errorf("unsupported cloud events spec version %q", ce.SpecVersion)
+	}
+	if ce.ID == "" {
+		return uevt, errors.New("cloud event without id")
+	}
+	if len(ce.Data) == 0 {
+		ce.Data = json.RawMessage("null")
+	}
+	if !json.Valid(ce.Data) {
+		return uevt, errors.New("cloud event data is not valid json")
+	}
+
+	uevt.Metadata = Metadata{
+		ID:            ce.ID,
+		CorrelationID: ce.CorrelationID,
+		Timestamp:     ce.Time,
+		Source:        ce.Source,
+		Type:          ce.Type,
+		Subject:       ce.Subject,
+	}
+	uevt.Payload = ce.Data
+	return uevt, nil
+}
+
+// cloudEventFromHeaders reads the attributes of a CloudEvent sent in binary mode.
+func cloudEventFromHeaders(headers amqp.Table) (cloudEvent, error) {
+	attribute := func(name string) string {
+		value, _ := headers[cloudEventsHeaderPrefix+name].(string)
+		return value
+	}
+
+	ce := cloudEvent{
+		SpecVersion:   attribute("specversion"),
+		ID:            attribute("id"),
+		Source:        attribute("source"),
+		Type:          attribute("type"),
+		Subject:       attribute("subject"),
+		CorrelationID: attribute("correlationid"),
+	}
+
+	switch t := headers[cloudEventsHeaderPrefix+"time"].(type) {
+	case time.Time:
+		ce.Time = t
+	case string:
+		parsed, err := time.Parse(time.RFC3339Nano, t)
+		if err != nil {
+			return ce, fmt.Errorf("invalid cloud event time: %w", err)
+		}
+		ce.Time = parsed
+	}
+
+	return ce, nil
+}
@@ -0,0 +1,94 @@
+package bunnify
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+func TestCloudEvents(t *testing.T) {
+	type orderCreated struct {
+		ID string `json:"id"`
+	}
+
+	event := NewPublishableEvent(
+		orderCreated{ID: "order"},
+		WithSource("/orders"),
+		WithSubject("order"))
+
+	for _, format := range []EventFormat{EventFormatCloudEventsBinary, EventFormatCloudEventsStructured} {
+		t.Run(string(format), func(t *testing.T) {
+			// Setup
+			publisher := Publisher{options: publisherOption{eventFormat: format}}
+
+			// Exercise
+			publishing, err := publisher.newPublishing(context.TODO(), "order.created", event)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			delivery := amqp.Delivery{
+				Headers:     publishing.Headers,
+				ContentType: publishing.ContentType,
+				Body:        publishing.Body,
+			}
+			uevt, err := decodeEvent(delivery, DeliveryInfo{}, false)
+
+			// Assert
+			if err != nil {
+				t.Fatal(err)
+			}
+			if uevt.ID != event.ID || uevt.CorrelationID != event.CorrelationID {
+				t.Fatalf("unexpected metadata %+v", uevt.Metadata)
+			}
+			if !uevt.Timestamp.Equal(event.Timestamp) {
+				t.Fatalf("expected timestamp %s, got %s", event.Timestamp, uevt.Timestamp)
+			}
+			if uevt.Source != "/orders" || uevt.Subject != "order" || uevt.Type != "order.created" {
+				t.Fatalf("unexpected attributes %+v", uevt.Metadata)
+			}
+
+			var payload orderCreated
+			if err := json.Unmarshal(uevt.Payload, &payload); err != nil {
+				t.Fatal(err)
+			}
+			if payload.ID != "order" {
+				t.Fatalf("unexpected payload %+v", payload)
+			}
+		})
+	}
+
+	t.Run("When binary mode is used the payload is the whole body", func(t *testing.T) {
+		// Setup
+		publisher := Publisher{options: publisherOption{eventFormat: EventFormatCloudEventsBinary}}
+
+		// Exercise
+		publishing, err := publisher.newPublishing(context.TODO(), "order.created", event)
+
+		// Assert
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(publishing.Body) != `{"id":"order"}` {
+			t.Fatalf("unexpected body %s", publishing.Body)
+		}
+		if publishing.Headers["cloudEvents:specversion"] != "1.0" {
+			t.Fatalf("unexpected spec version %v", publishing.Headers["cloudEvents:specversion"])
+		}
+	})
+
+	t.Run("When spec version is not supported", func(t *testing.T) {
+		// Exercise
+		_, err := decodeEvent(amqp.Delivery{
+			ContentType: cloudEventsContentType,
+			Body:        []byte(`{"specversion":"0.3","id":"1","source":"/orders","type":"order.created"}`),
+		}, DeliveryInfo{}, false)
+
+		// Assert
+		if err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
@@ -6,17 +6,59 @@ import (
 )
 
 // Metadata holds the metadata of an event.
+// Source, Type and Subject map onto the CloudEvents attributes with the same name.
 type Metadata struct {
 	ID            string    `json:"id"`
 	CorrelationID string    `json:"correlationId"`
 	Timestamp     time.Time `json:"timestamp"`
+	Source        string    `json:"source,omitempty"`
+	Type          string    `json:"type,omitempty"`
+	Subject       string    `json:"subject,omitempty"`
 }
 
 // DeliveryInfo holds information of original queue, exchange and routing keys.
+// It also holds the details of the delivery, such as the dead letter history and properties.
 type DeliveryInfo struct {
 	Queue      string
 	Exchange   string
 	RoutingKey string
+	// Redelivered is true if the event was delivered before but not acknowledged.
+	Redelivered bool
+	// DeliveryCount is the amount of times the server redelivered the event after a NACK.
+	// It is only tracked by quorum queues.
+	DeliveryCount int
+	// RetryCount is the amount of times the event was already retried.
+	RetryCount int
+	// Deaths holds the dead letter history, the most recent first.
+	Deaths     []Death
+	Properties Properties
+	Headers    map[string]any
+}
+
+// Death holds the information of an event being dead lettered from a queue.
+type Death struct {
+	Queue       string
+	Exchange    string
+	RoutingKeys []string
+	Reason      string
+	Count       int64
+	Time        time.Time
+}
+
+// Properties holds the AMQP properties of the delivered event.
+type Properties struct {
+	ContentType     string
+	ContentEncoding string
+	DeliveryMode    uint8
+	Priority        uint8
+	Expiration      string
+	AppID           string
+	ReplyTo         string
+	Type            string
+	UserID          string
+	MessageID       string
+	CorrelationID   string
+	Timestamp       time.Time
 }
 
 // ConsumableEvent[T] represents an event that can be consumed.
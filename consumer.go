@@ -3,16 +3,58 @@ package bunnify
 import (
 	"errors"
 	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/google/uuid"
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
 // Consumer is used for consuming to events from an specified queue.
+// Its state is shared by every copy, so it can be passed around by value.
 type Consumer struct {
+	*consumerCore
+}
+
+// consumerCore holds the options and the state of the consumption of a Consumer.
+type consumerCore struct {
 	queueName     string
 	initialized   bool
 	options       consumerOption
-	getNewChannel func() (*amqp.Channel, bool)
+	getNewChannel func(stop <-chan struct{}) (*amqp.Channel, bool)
+
+	// deferredQueueReady is set once the deferred queue is declared, or verified with passive declarations
+	deferredQueueReady atomic.Bool
+
+	// handlersMu guards the handlers, so they can be changed while consuming
+	handlersMu sync.RWMutex
+
+	// bindingsMu serializes the changes of handlers, which bind or unbind the queue without holding handlersMu
+	bindingsMu sync.Mutex
+
+	// inFlight counts the events being handled at the moment
+	inFlight atomic.Int64
+
+	// lastOffset is the offset of the last stream event handled successfully, to resume from it
+	lastOffset atomic.Pointer[int64]
+
+	// mu guards the fields below, which describe the current consumption
+	mu          sync.Mutex
+	channel     *consumerChannel
+	consumerTag string
+	parallel    bool
+	paused      bool
+	stopping    bool
+	drained     chan struct{}
+	stop        chan struct{}
+	done        chan struct{}
+	state       ConsumerState
+	lastErr     error
+	listeners   []chan<- StateChange
+
+	lastHandlerErr   error
+	lastHandlerErrAt time.Time
 }
 
 // NewConsumer creates a consumer for a given queue using the specified connection.
@@ -25,45 +67,66 @@ func (c *Connection) NewConsumer(
 
 	options := consumerOption{
 		notificationCh: c.options.notificationChannel,
+		tracerProvider: c.options.tracerProvider,
+		managementAPI:  c.managementAPI,
 		handlers:       make(map[string]wrappedHandler, 0),
+		rawHandlers:    make(map[string]struct{}, 0),
+		batchHandlers:  make(map[string]batchHandler, 0),
+		exchangeKind:   ExchangeKindDirect,
 		prefetchCount:  20,
 		prefetchSize:   0,
+
+		reconnectBackoff:    100 * time.Millisecond,
+		maxReconnectBackoff: 10 * time.Second,
 	}
 	for _, opt := range opts {
 		opt(&options)
 	}
+	if options.name == "" {
+		options.name = queueName
+	}
 
-	return Consumer{
+	return Consumer{&consumerCore{
 		queueName: queueName,
 		options:   options,
-		getNewChannel: func() (*amqp.Channel, bool) {
-			return c.getNewChannel(NotificationSourceConsumer)
+		getNewChannel: func(stop <-chan struct{}) (*amqp.Channel, bool) {
+			return c.getNewChannelUntil(NotificationSourceConsumer, stop)
 		},
-	}
+	}}
 }
 
 // AddHandlerToConsumer adds a handler for the given routing key.
 // It is another way to add handlers when the consumer is already created and cannot use the options.
+//
+// Deprecated: use AddHandler, which also binds the routing key when the consumer is already
+// consuming and returns the error if it cannot. Here the handler is added anyway and the error
+// is sent to the notification channel.
 func AddHandlerToConsumer[T any](consumer *Consumer, routingKey string, handler EventHandler[T]) {
-	consumer.options.handlers[routingKey] = newWrappedHandler(handler)
+	wrapped := newWrappedHandler(handler)
+	if err := consumer.addHandler(routingKey, wrapped); err != nil {
+		consumer.handlersMu.Lock()
+		consumer.setHandler(routingKey, wrapped)
+		consumer.handlersMu.Unlock()
+		notifyHandlerBindingFailed(consumer.options.notificationCh, consumer.queueName, routingKey, err)
+	}
 }
 
 // Consume will start consuming events from the indicated queue.
-// The first time this function is called it will return error if
-// handlers or default handler are not specified or if queues, exchanges,
-// bindings and qos creation don't succeed. In case this function gets called
-// recursively due to channel reconnection, the errors will be pushed to
-// the notification channel (if one has been indicated in the connection).
+// It will return error if handlers or default handler are not specified or if
+// queues, exchanges, bindings and qos creation don't succeed. Once consuming,
+// the consumer is supervised: if the channel is lost, it reconnects with backoff
+// and the errors are pushed to the notification channel (if one has been indicated
+// in the connection). The current state can be checked with State.
 func (c *Consumer) Consume() error {
 	return c.consume(false)
 }
 
 // ConsumeParallel will start consuming events for the indicated queue.
-// The first time this function is called it will return error if
-// handlers or default handler are not specified and also if queues, exchanges,
-// bindings or qos creation don't succeed. In case this function gets called
-// recursively due to channel reconnection, the errors will be pushed to
-// the notification channel (if one has been indicated in the connection).
+// It will return error if handlers or default handler are not specified and also
+// if queues, exchanges, bindings or qos creation don't succeed. Once consuming,
+// the consumer is supervised: if the channel is lost, it reconnects with backoff
+// and the errors are pushed to the notification channel (if one has been indicated
+// in the connection). The current state can be checked with State.
 // The difference between this and the regular Consume is that this one fires
 // a go routine per each message received as opposed of sequentially.
 func (c *Consumer) ConsumeParallel() error {
@@ -71,51 +134,161 @@ func (c *Consumer) ConsumeParallel() error {
 }
 
 func (c *Consumer) consume(parallel bool) error {
-	channel, connectionClosed := c.getNewChannel()
+	c.mu.Lock()
+	c.paused = false
+	c.stopping = false
+	c.lastErr = nil
+	c.done = make(chan struct{})
+	c.stop = make(chan struct{})
+	c.mu.Unlock()
+
+	c.setState(ConsumerStateStarting, nil)
+
+	channel, deliveries, err := c.start(parallel)
+	if err != nil {
+		c.finish(ConsumerStateFailed, err)
+		return err
+	}
+
+	c.setState(ConsumerStateConsuming, nil)
+	go c.supervise(channel, deliveries, parallel)
+	return nil
+}
+
+// start obtains a channel and starts consuming from the queue, declaring
+// the exchanges, queues and bindings the first time.
+func (c *Consumer) start(parallel bool) (*consumerChannel, <-chan amqp.Delivery, error) {
+	if c.isPaused() {
+		return nil, nil, errConsumerPaused
+	}
+
+	channel, connectionClosed := c.getNewChannel(c.stopSignal())
 	if connectionClosed {
-		return errConnectionClosedByUser
+		return nil, nil, errConnectionClosedByUser
+	}
+
+	// Paused or stopped while waiting for a channel
+	if channel == nil {
+		return nil, nil, errConsumerPaused
 	}
 
-	// If obtained channel is closed, try again
 	if channel.IsClosed() {
-		return c.consume(parallel)
+		return nil, nil, fmt.Errorf("obtained channel is closed")
 	}
 
-	if !c.initialized {
-		if c.options.defaultHandler == nil && len(c.options.handlers) == 0 {
-			return fmt.Errorf("no handlers specified")
+	consumerChannel, deliveries, err := c.startOnChannel(channel, parallel)
+	if err != nil {
+		if !channel.IsClosed() {
+			channel.Close()
 		}
+		return nil, nil, err
+	}
 
-		if err := c.createExchanges(channel); err != nil {
-			return fmt.Errorf("failed to declare exchange: %w", err)
-		}
+	return consumerChannel, deliveries, nil
+}
 
-		if err := c.createQueues(channel); err != nil {
-			return fmt.Errorf("failed to declare queue: %w", err)
-		}
+func (c *Consumer) startOnChannel(amqpChannel *amqp.Channel, parallel bool) (*consumerChannel, <-chan amqp.Delivery, error) {
+	if err := c.initialize(amqpChannel); err != nil {
+		return nil, nil, err
+	}
 
-		if err := c.queueBind(channel); err != nil {
-			return fmt.Errorf("failed to bind queue: %w", err)
-		}
+	if err := amqpChannel.Qos(c.options.prefetchCount, c.options.prefetchSize, false); err != nil {
+		return nil, nil, fmt.Errorf("failed to set qos: %w", err)
+	}
 
-		c.initialized = true
+	channel, err := newConsumerChannel(amqpChannel)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	if err := channel.Qos(c.options.prefetchCount, c.options.prefetchSize, false); err != nil {
-		return fmt.Errorf("failed to set qos: %w", err)
+	args, err := c.consumeArgs()
+	if err != nil {
+		return nil, nil, err
 	}
 
-	deliveries, err := channel.Consume(c.queueName, "", false, false, false, false, nil)
+	consumerTag := fmt.Sprintf("bunnify-%s", uuid.NewString())
+	deliveries, err := channel.Consume(c.queueName, consumerTag, false, c.options.exclusive, false, false, args)
 	if err != nil {
-		return fmt.Errorf("failed to establish consuming from queue: %w", err)
+		return nil, nil, fmt.Errorf("failed to establish consuming from queue: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Paused while starting, the consuming tag was not known yet so it could not be cancelled
+	if c.paused {
+		return nil, nil, errConsumerPaused
+	}
+
+	c.channel = channel
+	c.consumerTag = consumerTag
+	c.parallel = parallel
+	c.drained = make(chan struct{})
+	return channel, deliveries, nil
+}
+
+// initialize validates the options and declares the exchanges, queues and bindings.
+// It only happens once, as reconnecting does not need to declare them again.
+func (c *Consumer) initialize(channel *amqp.Channel) error {
+	c.handlersMu.Lock()
+	defer c.handlersMu.Unlock()
+
+	if c.initialized {
+		return nil
+	}
+
+	if c.options.defaultHandler == nil &&
+		len(c.options.handlers) == 0 &&
+		len(c.options.headerHandlers) == 0 &&
+		len(c.options.batchHandlers) == 0 {
+		return fmt.Errorf("no handlers specified")
+	}
+
+	if err := c.validateBatchHandlers(); err != nil {
+		return err
+	}
+
+	if c.options.failureDetails && c.options.deadLetterQueue == "" {
+		return fmt.Errorf("failure details require a dead letter queue")
+	}
+
+	if c.options.retryRepublish && c.maxRetries() == 0 {
+		return fmt.Errorf("retries by republish require retries")
+	}
+
+	if c.options.singleActive && c.options.exclusive {
+		return fmt.Errorf("single active consumer and exclusive consumer cannot be used together")
+	}
+
+	if err := c.validateStream(); err != nil {
+		return fmt.Errorf("invalid stream options: %w", err)
+	}
+
+	if err := c.validateQueueArguments(); err != nil {
+		return fmt.Errorf("invalid queue arguments: %w", err)
+	}
+
+	if c.options.passive {
+		if err := c.verifyTopology(c.topologySteps()); err != nil {
+			return err
+		}
+		c.initialized = true
+		return nil
+	}
+
+	if err := c.createExchanges(channel); err != nil {
+		return fmt.Errorf("failed to declare exchange: %w", err)
 	}
 
-	if parallel {
-		go c.parallelLoop(channel, deliveries)
-	} else {
-		go c.loop(channel, deliveries)
+	if err := c.createQueues(channel); err != nil {
+		return fmt.Errorf("failed to declare queue: %w", err)
 	}
 
+	if err := c.queueBind(channel); err != nil {
+		return fmt.Errorf("failed to bind queue: %w", err)
+	}
+
+	c.initialized = true
 	return nil
 }
 
@@ -125,7 +298,7 @@ func (c *Consumer) createExchanges(channel *amqp.Channel) error {
 	if c.options.exchange != "" {
 		errs = append(errs, channel.ExchangeDeclare(
 			c.options.exchange,
-			"direct",
+			string(c.options.exchangeKind),
 			true,  // durable
 			false, // auto-deleted
 			false, // internal
@@ -136,7 +309,7 @@ func (c *Consumer) createExchanges(channel *amqp.Channel) error {
 
 	if c.options.deadLetterQueue != "" {
 		errs = append(errs, channel.ExchangeDeclare(
-			fmt.Sprintf("%s-exchange", c.options.deadLetterQueue),
+			c.deadLetterExchange(),
 			"direct",
 			true,  // durable
 			false, // auto-deleted
@@ -152,22 +325,19 @@ func (c *Consumer) createExchanges(channel *amqp.Channel) error {
 func (c *Consumer) createQueues(channel *amqp.Channel) error {
 	errs := make([]error, 0)
 
-	amqpTable := amqp.Table{}
-
-	if c.options.quorumQueue {
-		amqpTable["x-queue-type"] = "quorum"
-	}
+	amqpTable := c.queueArguments()
 
-	if c.options.retries > 0 {
+	if c.options.retries > 0 && !c.retriesByRepublish() {
 		if !c.options.quorumQueue {
 			return fmt.Errorf("to enable retries, you need to use quorum queues.")
 		}
 	}
 
-	if c.options.deadLetterQueue != "" {
-		amqpTable["x-dead-letter-exchange"] = fmt.Sprintf("%s-exchange", c.options.deadLetterQueue)
-		amqpTable["x-dead-letter-routing-key"] = ""
+	if err := c.createRetryQueues(channel); err != nil {
+		return err
+	}
 
+	if c.options.deadLetterQueue != "" {
 		_, err := channel.QueueDeclare(
 			c.options.deadLetterQueue,
 			true,  // durable
@@ -179,6 +349,18 @@ func (c *Consumer) createQueues(channel *amqp.Channel) error {
 		errs = append(errs, err)
 	}
 
+	if c.options.parkingLotQueue != "" {
+		_, err := channel.QueueDeclare(
+			c.options.parkingLotQueue,
+			true,  // durable
+			false, // auto-delete
+			false, // exclusive
+			false, // no-wait
+			amqp.Table{},
+		)
+		errs = append(errs, err)
+	}
+
 	_, err := channel.QueueDeclare(
 		c.queueName,
 		true,  // durable
@@ -192,10 +374,48 @@ func (c *Consumer) createQueues(channel *amqp.Channel) error {
 	return errors.Join(errs...)
 }
 
+// queueArguments returns the arguments used to declare the consumer queue.
+func (c *Consumer) queueArguments() amqp.Table {
+	amqpTable := amqp.Table{}
+
+	if c.options.quorumQueue {
+		amqpTable["x-queue-type"] = "quorum"
+	}
+
+	if c.options.streamQueue {
+		amqpTable["x-queue-type"] = "stream"
+	}
+
+	if c.options.singleActive {
+		amqpTable["x-single-active-consumer"] = true
+	}
+
+	c.setQueueArguments(amqpTable)
+
+	if c.options.deadLetterQueue != "" {
+		amqpTable["x-dead-letter-exchange"] = c.deadLetterExchange()
+		amqpTable["x-dead-letter-routing-key"] = ""
+	}
+
+	return amqpTable
+}
+
 func (c *Consumer) queueBind(channel *amqp.Channel) error {
 	errs := make([]error, 0)
 
-	if c.options.exchange != "" {
+	if c.options.exchange != "" && c.options.exchangeKind == ExchangeKindHeaders {
+		for _, h := range c.options.headerHandlers {
+			errs = append(errs, channel.QueueBind(
+				c.queueName,
+				"",
+				c.options.exchange,
+				false,
+				h.bindingArgs(),
+			))
+		}
+	}
+
+	if c.options.exchange != "" && c.options.exchangeKind != ExchangeKindHeaders {
 		for routingKey := range c.options.handlers {
 			errs = append(errs, channel.QueueBind(
 				c.queueName,
@@ -205,13 +425,23 @@ func (c *Consumer) queueBind(channel *amqp.Channel) error {
 				nil,
 			))
 		}
+
+		for routingKey := range c.options.batchHandlers {
+			errs = append(errs, channel.QueueBind(
+				c.queueName,
+				routingKey,
+				c.options.exchange,
+				false,
+				nil,
+			))
+		}
 	}
 
 	if c.options.deadLetterQueue != "" {
 		errs = append(errs, channel.QueueBind(
 			c.options.deadLetterQueue,
 			"",
-			fmt.Sprintf("%s-exchange", c.options.deadLetterQueue),
+			c.deadLetterExchange(),
 			false,
 			nil,
 		))
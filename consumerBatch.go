@@ -0,0 +1,208 @@
+package bunnify
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+type batchHandler struct {
+	size    int
+	maxWait time.Duration
+	handler wrappedBatchHandler
+}
+
+type batchItem struct {
+	delivery     amqp.Delivery
+	deliveryInfo DeliveryInfo
+	event        unmarshalEvent
+	startTime    time.Time
+}
+
+type pendingBatch struct {
+	items []batchItem
+	timer *time.Timer
+}
+
+func (p *pendingBatch) firstTag() uint64 {
+	return p.items[0].delivery.DeliveryTag
+}
+
+func (p *pendingBatch) lastTag() uint64 {
+	return p.items[len(p.items)-1].delivery.DeliveryTag
+}
+
+// batcher accumulates the events for the batch handlers of a single channel,
+// as delivery tags, and therefore multiple acknowledgements, are scoped to the channel.
+type batcher struct {
+	mu       sync.Mutex
+	consumer *Consumer
+	channel  *consumerChannel
+	offsets  *offsetTracker
+	parallel bool
+	stopped  bool
+	pending  map[string]*pendingBatch
+	settling map[*pendingBatch]struct{}
+
+	// flushing tracks the batch handlers running, so stopping waits for them to settle their events
+	flushing sync.WaitGroup
+}
+
+func newBatcher(consumer *Consumer, channel *consumerChannel, offsets *offsetTracker, parallel bool) *batcher {
+	return &batcher{
+		consumer: consumer,
+		channel:  channel,
+		offsets:  offsets,
+		parallel: parallel,
+		pending:  make(map[string]*pendingBatch),
+		settling: make(map[*pendingBatch]struct{}),
+	}
+}
+
+func (c *Consumer) validateBatchHandlers() error {
+	for routingKey, batch := range c.options.batchHandlers {
+		if batch.size <= 0 || batch.maxWait <= 0 {
+			return fmt.Errorf("batch handler for %s requires a positive size and max wait", routingKey)
+		}
+		if c.options.prefetchCount > 0 && batch.size > c.options.prefetchCount {
+			return fmt.Errorf("batch size %d for %s exceeds the prefetch count %d", batch.size, routingKey, c.options.prefetchCount)
+		}
+	}
+	return nil
+}
+
+// add appends the item to the pending batch of the routing key
+// and flushes it when the size of the batch is reached.
+func (b *batcher) add(routingKey string, handler batchHandler, item batchItem) {
+	b.mu.Lock()
+	if b.stopped {
+		b.mu.Unlock()
+		return
+	}
+
+	p, ok := b.pending[routingKey]
+	if !ok {
+		p = &pendingBatch{}
+		p.timer = time.AfterFunc(handler.maxWait, func() {
+			b.flush(routingKey, handler, p)
+		})
+		b.pending[routingKey] = p
+	}
+
+	p.items = append(p.items, item)
+	full := len(p.items) >= handler.size
+	b.mu.Unlock()
+
+	if full {
+		p.timer.Stop()
+		b.flush(routingKey, handler, p)
+	}
+}
+
+// flush invokes the batch handler for the pending batch if it was not already flushed.
+func (b *batcher) flush(routingKey string, handler batchHandler, p *pendingBatch) {
+	b.mu.Lock()
+	if b.stopped || b.pending[routingKey] != p {
+		b.mu.Unlock()
+		return
+	}
+	delete(b.pending, routingKey)
+	b.settling[p] = struct{}{}
+	b.flushing.Add(1)
+	b.mu.Unlock()
+	defer b.flushing.Done()
+
+	events := make([]unmarshalEvent, len(p.items))
+	for i, item := range p.items {
+		events[i] = item.event
+	}
+
+	b.consumer.inFlight.Add(int64(len(events)))
+	tracingCtx, span := b.consumer.startBatchSpan(p.items)
+	err := handler.handler(tracingCtx, events)
+	endSpan(span, err)
+	b.settle(p, err)
+	b.consumer.inFlight.Add(-int64(len(events)))
+
+	b.mu.Lock()
+	delete(b.settling, p)
+	b.mu.Unlock()
+}
+
+// settle acknowledges the whole batch with a single multiple acknowledgement when possible.
+// Otherwise each event is settled on its own, using the error reported for its index if any.
+func (b *batcher) settle(p *pendingBatch, err error) {
+	c := b.consumer
+
+	var batchErr *BatchError
+	isBatchErr := errors.As(err, &batchErr)
+
+	for i, item := range p.items {
+		if err == nil || (isBatchErr && batchErr.Errors[i] == nil) {
+			c.markProcessed(item.event.ID)
+		}
+	}
+
+	if err == nil && b.canAckMultiple(p) {
+		_ = p.items[len(p.items)-1].delivery.Ack(true)
+		for _, item := range p.items {
+			c.offsetSettled(b.offsets, item.delivery)
+			elapsed := time.Since(item.startTime).Milliseconds()
+			notifyEventHandlerSucceed(c.options.notificationCh, item.deliveryInfo.RoutingKey, elapsed)
+			eventAck(c.queueName, item.deliveryInfo.RoutingKey, elapsed)
+		}
+		return
+	}
+
+	for i, item := range p.items {
+		itemErr := err
+		if isBatchErr {
+			itemErr = batchErr.Errors[i]
+		}
+		c.settle(b.channel, item.delivery, item.deliveryInfo, item.startTime, itemErr)
+		c.offsetSettled(b.offsets, item.delivery)
+	}
+}
+
+// canAckMultiple returns true if no other batch has unsettled events with a lower
+// delivery tag, as a multiple acknowledgement would settle those as well.
+// When consuming in parallel, other events can be in flight, so it is never safe.
+func (b *batcher) canAckMultiple(p *pendingBatch) bool {
+	if b.parallel {
+		return false
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, other := range b.pending {
+		if other.firstTag() < p.lastTag() {
+			return false
+		}
+	}
+	for other := range b.settling {
+		if other != p && other.firstTag() < p.lastTag() {
+			return false
+		}
+	}
+	return true
+}
+
+// stop discards the pending batches, their events will be redelivered
+// by the server as the channel they were delivered on is closed.
+// The batches already flushed, including the ones flushed by the max wait timer,
+// are waited for, so their events are settled before the channel is closed.
+func (b *batcher) stop() {
+	b.mu.Lock()
+	b.stopped = true
+	for routingKey, p := range b.pending {
+		p.timer.Stop()
+		delete(b.pending, routingKey)
+	}
+	b.mu.Unlock()
+
+	b.flushing.Wait()
+}
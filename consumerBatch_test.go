@@ -0,0 +1,52 @@
+package bunnify
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+func TestBatcherStop(t *testing.T) {
+	t.Run("When stopping it waits for the batches flushed by the timer", func(t *testing.T) {
+		// Setup
+		batches := newBatcher(&Consumer{&consumerCore{queueName: "queue"}}, nil, nil, false)
+
+		started := make(chan struct{})
+		release := make(chan struct{})
+		handler := batchHandler{
+			size:    10,
+			maxWait: time.Millisecond,
+			handler: func(ctx context.Context, events []unmarshalEvent) error {
+				close(started)
+				<-release
+				return nil
+			},
+		}
+
+		batches.add("order.created", handler, batchItem{delivery: amqp.Delivery{DeliveryTag: 1}})
+		<-started
+
+		// Exercise
+		stopped := make(chan struct{})
+		go func() {
+			batches.stop()
+			close(stopped)
+		}()
+
+		// Assert
+		select {
+		case <-stopped:
+			t.Fatal("expected stop to wait for the batch handler")
+		case <-time.After(20 * time.Millisecond):
+		}
+
+		close(release)
+		select {
+		case <-stopped:
+		case <-time.After(time.Second):
+			t.Fatal("expected stop to return once the batch is settled")
+		}
+	})
+}
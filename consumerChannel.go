@@ -0,0 +1,116 @@
+package bunnify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// consumerChannel is the channel the consumer receives the events from. It is in confirm mode,
+// so the copies republished for retries, dead letter or the parking lot are only considered
+// published once the server confirms them.
+type consumerChannel struct {
+	*amqp.Channel
+
+	// mu serializes the republishes, so a return can only belong to the publish waiting for its confirmation,
+	// or to a previous one which confirmation timed out
+	mu      sync.Mutex
+	returns <-chan amqp.Return
+
+	// active is set once the channel receives an event with single active consumer enabled
+	active atomic.Bool
+}
+
+// returnsBuffer is the capacity of the returns channel. The returns are drained on every republish,
+// but the buffer avoids blocking the connection while a late one waits to be drained.
+const returnsBuffer = 16
+
+func newConsumerChannel(channel *amqp.Channel) (*consumerChannel, error) {
+	if err := channel.Confirm(false); err != nil {
+		return nil, fmt.Errorf("failed to enable publisher confirms: %w", err)
+	}
+
+	return &consumerChannel{
+		Channel: channel,
+		returns: channel.NotifyReturn(make(chan amqp.Return, returnsBuffer)),
+	}, nil
+}
+
+// republish sends a copy of the delivery with the given headers to the exchange and routing key
+// and waits for the server confirmation. Events that are not confirmed or cannot be routed return
+// an error, so the original is not acknowledged.
+func (ch *consumerChannel) republish(
+	exchange, routingKey string,
+	delivery amqp.Delivery,
+	headers amqp.Table,
+	expiration string) error {
+
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+
+	// The returns left belong to previous republishes which confirmation timed out
+	ch.drainReturns(func(amqp.Return) {})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	confirmation, err := ch.PublishWithDeferredConfirmWithContext(ctx, exchange, routingKey, true, false, amqp.Publishing{
+		Headers:         headers,
+		ContentType:     delivery.ContentType,
+		ContentEncoding: delivery.ContentEncoding,
+		DeliveryMode:    delivery.DeliveryMode,
+		Priority:        delivery.Priority,
+		CorrelationId:   delivery.CorrelationId,
+		ReplyTo:         delivery.ReplyTo,
+		Expiration:      expiration,
+		MessageId:       delivery.MessageId,
+		Timestamp:       delivery.Timestamp,
+		Type:            delivery.Type,
+		AppId:           delivery.AppId,
+		Body:            delivery.Body,
+	})
+	if err != nil {
+		return err
+	}
+
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		return err
+	}
+	if !acked {
+		return errors.New("republish was not confirmed by the server")
+	}
+
+	// The server sends the return before the confirmation. A late return of a previous
+	// republish can arrive as well, so the returns are matched by the message id.
+	var returned *amqp.Return
+	ch.drainReturns(func(r amqp.Return) {
+		if r.MessageId == delivery.MessageId {
+			returned = &r
+		}
+	})
+	if returned != nil {
+		return fmt.Errorf("event could not be routed to %s with routing key %s: %s", returned.Exchange, returned.RoutingKey, returned.ReplyText)
+	}
+	return nil
+}
+
+// drainReturns passes the returns received so far to the function, without waiting for more.
+func (ch *consumerChannel) drainReturns(f func(r amqp.Return)) {
+	for {
+		select {
+		case r, ok := <-ch.returns:
+			if !ok {
+				return
+			}
+			f(r)
+		default:
+			return
+		}
+	}
+}
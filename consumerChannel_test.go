@@ -0,0 +1,70 @@
+package bunnify
+
+import (
+	"testing"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+func TestConsumerChannelDrainReturns(t *testing.T) {
+	t.Run("When returns are buffered they are drained without waiting for more", func(t *testing.T) {
+		// Setup
+		returns := make(chan amqp.Return, returnsBuffer)
+		returns <- amqp.Return{MessageId: "previous"}
+		returns <- amqp.Return{MessageId: "current"}
+		channel := consumerChannel{returns: returns}
+
+		// Exercise
+		drained := make([]string, 0)
+		channel.drainReturns(func(r amqp.Return) {
+			drained = append(drained, r.MessageId)
+		})
+
+		// Assert
+		if len(drained) != 2 || drained[0] != "previous" || drained[1] != "current" {
+			t.Fatalf("unexpected returns drained %v", drained)
+		}
+		if len(returns) != 0 {
+			t.Fatalf("expected no returns left, got %d", len(returns))
+		}
+	})
+}
+
+func TestConsumerMarkActive(t *testing.T) {
+	t.Run("When a channel receives events the consumer is reported active once per channel", func(t *testing.T) {
+		// Setup
+		notifications := make(chan Notification, 3)
+		consumer := Consumer{&consumerCore{
+			queueName: "queue",
+			options:   consumerOption{singleActive: true, notificationCh: notifications},
+		}}
+		first, second := &consumerChannel{}, &consumerChannel{}
+
+		// Exercise
+		consumer.markActive(first)
+		consumer.markActive(first)
+		consumer.markActive(second)
+
+		// Assert
+		if len(notifications) != 2 {
+			t.Fatalf("expected 2 notifications, got %d", len(notifications))
+		}
+	})
+
+	t.Run("When single active consumer is not enabled nothing is reported", func(t *testing.T) {
+		// Setup
+		notifications := make(chan Notification, 1)
+		consumer := Consumer{&consumerCore{
+			queueName: "queue",
+			options:   consumerOption{notificationCh: notifications},
+		}}
+
+		// Exercise
+		consumer.markActive(&consumerChannel{})
+
+		// Assert
+		if len(notifications) != 0 {
+			t.Fatalf("expected no notifications, got %d", len(notifications))
+		}
+	})
+}
@@ -0,0 +1,106 @@
+package bunnify
+
+import (
+	"fmt"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// AddHandler adds a handler for the given routing key, replacing the existing one if any.
+// It is safe to use while consuming: the routing key is bound to the queue if the
+// WithBindingToExchange is supplied, and the handler is invoked for the next events.
+func AddHandler[T any](consumer *Consumer, routingKey string, handler EventHandler[T]) error {
+	return consumer.addHandler(routingKey, newWrappedHandler(handler))
+}
+
+// RemoveHandler removes the handler of the given routing key. It is safe to use while consuming:
+// the routing key is unbound from the queue first, so no more events are routed for it.
+// The events already in the queue are handled by the default handler, if any.
+func (c *Consumer) RemoveHandler(routingKey string) error {
+	c.bindingsMu.Lock()
+	defer c.bindingsMu.Unlock()
+
+	c.handlersMu.RLock()
+	_, ok := c.options.handlers[routingKey]
+	c.handlersMu.RUnlock()
+	if !ok {
+		return fmt.Errorf("no handler for routing key %s", routingKey)
+	}
+
+	return c.changeHandlers(
+		func(channel *amqp.Channel) error {
+			if err := channel.QueueUnbind(c.queueName, routingKey, c.options.exchange, nil); err != nil {
+				return fmt.Errorf("failed to unbind queue: %w", err)
+			}
+			return nil
+		},
+		func() {
+			delete(c.options.handlers, routingKey)
+			delete(c.options.rawHandlers, routingKey)
+		})
+}
+
+func (c *Consumer) addHandler(routingKey string, handler wrappedHandler) error {
+	c.bindingsMu.Lock()
+	defer c.bindingsMu.Unlock()
+
+	return c.changeHandlers(
+		func(channel *amqp.Channel) error {
+			if err := channel.QueueBind(c.queueName, routingKey, c.options.exchange, false, nil); err != nil {
+				return fmt.Errorf("failed to bind queue: %w", err)
+			}
+			return nil
+		},
+		func() {
+			c.setHandler(routingKey, handler)
+		})
+}
+
+// changeHandlers changes the bindings and then the handlers. The bindings are changed without
+// holding the handlers lock, so events keep being dispatched while waiting for the server. Before
+// consuming, the bindings are not changed as they are declared when the consumer is initialized.
+// If the consumer gets initialized meanwhile, the bindings are changed before the handlers.
+func (c *Consumer) changeHandlers(bind func(channel *amqp.Channel) error, change func()) error {
+	for {
+		c.handlersMu.RLock()
+		initialized := c.initialized
+		c.handlersMu.RUnlock()
+
+		if initialized {
+			if err := c.withBindingChannel(bind); err != nil {
+				return err
+			}
+		}
+
+		c.handlersMu.Lock()
+		if c.initialized == initialized {
+			change()
+			c.handlersMu.Unlock()
+			return nil
+		}
+		c.handlersMu.Unlock()
+	}
+}
+
+// setHandler sets the handler of the routing key, which is not raw unless specified with WithRawHandler.
+func (c *Consumer) setHandler(routingKey string, handler wrappedHandler) {
+	c.options.handlers[routingKey] = handler
+	delete(c.options.rawHandlers, routingKey)
+}
+
+// withBindingChannel runs the function with a new channel when the queue is bound to an exchange
+// by routing key, unless the bindings are not managed by the consumer as declarations are passive.
+// The channel used for consuming is not shared, as an error would close it.
+func (c *Consumer) withBindingChannel(fn func(channel *amqp.Channel) error) error {
+	if c.options.exchange == "" || c.options.exchangeKind == ExchangeKindHeaders || c.options.passive {
+		return nil
+	}
+
+	channel, connectionClosed := c.getNewChannel(nil)
+	if connectionClosed {
+		return errConnectionClosedByUser
+	}
+	defer channel.Close()
+
+	return fn(channel)
+}
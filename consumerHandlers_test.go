@@ -0,0 +1,136 @@
+package bunnify
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+func TestAddRemoveHandler(t *testing.T) {
+	type orderCreated struct {
+		ID string `json:"id"`
+	}
+
+	handler := func(ctx context.Context, event ConsumableEvent[orderCreated]) error {
+		return nil
+	}
+
+	newConsumer := func() *Consumer {
+		return &Consumer{&consumerCore{
+			queueName: "queue",
+			options: consumerOption{
+				handlers:    make(map[string]wrappedHandler),
+				rawHandlers: make(map[string]struct{}),
+			},
+		}}
+	}
+
+	t.Run("When handlers are added and removed while dispatching", func(t *testing.T) {
+		// Setup
+		consumer := newConsumer()
+		wg := sync.WaitGroup{}
+
+		// Exercise
+		for range 10 {
+			wg.Go(func() {
+				consumer.handlersMu.RLock()
+				consumer.getHandler("order.created", nil)
+				consumer.handlersMu.RUnlock()
+			})
+		}
+		if err := AddHandler(consumer, "order.created", handler); err != nil {
+			t.Fatal(err)
+		}
+		wg.Wait()
+
+		// Assert
+		if _, _, ok := consumer.getHandler("order.created", nil); !ok {
+			t.Fatal("expected handler for order.created")
+		}
+
+		if err := consumer.RemoveHandler("order.created"); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, ok := consumer.getHandler("order.created", nil); ok {
+			t.Fatal("expected no handler for order.created")
+		}
+	})
+
+	t.Run("When removing a routing key without handler", func(t *testing.T) {
+		// Exercise
+		err := newConsumer().RemoveHandler("order.created")
+
+		// Assert
+		if err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("When binding waits for a channel events are still dispatched", func(t *testing.T) {
+		// Setup
+		consumer := newConsumer()
+		consumer.initialized = true
+		consumer.options.exchange = "exchange"
+		consumer.options.notificationCh = make(chan Notification, 1)
+
+		obtaining := make(chan struct{})
+		release := make(chan struct{})
+		consumer.getNewChannel = func(stop <-chan struct{}) (*amqp.Channel, bool) {
+			// The connection is down until released, then it is closed
+			close(obtaining)
+			<-release
+			return nil, true
+		}
+
+		added := make(chan error)
+		go func() {
+			added <- AddHandler(consumer, "order.created", handler)
+		}()
+		<-obtaining
+
+		// Exercise
+		dispatching := consumer.handlersMu.TryRLock()
+		if dispatching {
+			consumer.handlersMu.RUnlock()
+		}
+		close(release)
+		err := <-added
+
+		// Assert
+		if !dispatching {
+			t.Fatal("expected handlers not to be locked while binding")
+		}
+		if !errors.Is(err, errConnectionClosedByUser) {
+			t.Fatalf("expected connection closed error, got %v", err)
+		}
+		if _, _, ok := consumer.getHandler("order.created", nil); ok {
+			t.Fatal("expected handler not to be added when binding fails")
+		}
+	})
+
+	t.Run("When binding fails the deprecated function adds the handler anyway", func(t *testing.T) {
+		// Setup
+		consumer := newConsumer()
+		consumer.initialized = true
+		consumer.options.exchange = "exchange"
+		notifications := make(chan Notification, 1)
+		consumer.options.notificationCh = notifications
+		consumer.getNewChannel = func(stop <-chan struct{}) (*amqp.Channel, bool) {
+			return nil, true
+		}
+
+		// Exercise
+		AddHandlerToConsumer(consumer, "order.created", handler)
+
+		// Assert
+		if _, _, ok := consumer.getHandler("order.created", nil); !ok {
+			t.Fatal("expected handler for order.created")
+		}
+		if (<-notifications).Type != NotificationTypeError {
+			t.Fatal("expected notification type error")
+		}
+	})
+}
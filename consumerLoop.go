@@ -1,7 +1,6 @@
 package bunnify
 
 import (
-	"encoding/json"
 	"errors"
 	"sync"
 	"time"
@@ -9,108 +8,155 @@ import (
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
-func (c *Consumer) loop(channel *amqp.Channel, deliveries <-chan amqp.Delivery) {
-	mutex := sync.Mutex{}
+// loop handles the deliveries sequentially until the channel stops.
+func (c *Consumer) loop(channel *consumerChannel, deliveries <-chan amqp.Delivery) {
+	offsets := c.newOffsetTracker()
+	batches := newBatcher(c, channel, offsets, false)
 	for delivery := range deliveries {
-		c.handle(delivery, &mutex)
+		c.markActive(channel)
+		offsets.delivered(delivery)
+		c.handle(channel, delivery, batches)
 	}
+	batches.stop()
 
-	// If the for exits, it means the channel stopped. Close it and try to reconnect
+	// If the for exits, it means the channel stopped
 	if !channel.IsClosed() {
 		channel.Close()
 	}
-
-	err := c.Consume()
-	if errors.Is(err, errConnectionClosedByUser) {
-		return
-	}
-
-	notifyChannelLost(c.options.notificationCh, NotificationSourceConsumer)
-	if err != nil {
-		notifyChannelFailed(c.options.notificationCh, NotificationSourceConsumer, err)
-	}
 }
 
-func (c *Consumer) parallelLoop(channel *amqp.Channel, deliveries <-chan amqp.Delivery) {
-	mutex := sync.Mutex{}
+// parallelLoop handles each delivery in its own go routine until the channel stops.
+func (c *Consumer) parallelLoop(channel *consumerChannel, deliveries <-chan amqp.Delivery) {
+	offsets := c.newOffsetTracker()
+	batches := newBatcher(c, channel, offsets, true)
+	inFlight := sync.WaitGroup{}
 	for delivery := range deliveries {
-		go c.handle(delivery, &mutex)
+		c.markActive(channel)
+		offsets.delivered(delivery)
+		inFlight.Go(func() {
+			c.handle(channel, delivery, batches)
+		})
 	}
+	batches.stop()
 
-	if !channel.IsClosed() {
-		channel.Close()
+	// When paused, the handlers still running need the channel to settle their events
+	if c.isPaused() {
+		inFlight.Wait()
 	}
 
-	err := c.ConsumeParallel()
-	if errors.Is(err, errConnectionClosedByUser) {
-		return
+	if !channel.IsClosed() {
+		channel.Close()
 	}
+}
 
-	notifyChannelLost(c.options.notificationCh, NotificationSourceConsumer)
-	if err != nil {
-		notifyChannelFailed(c.options.notificationCh, NotificationSourceConsumer, err)
+// markActive notifies that the consumer became the single active consumer when the channel
+// receives its first event. This is a heuristic, as the server does not inform which consumer is
+// active: a consumer that is active on an empty queue is not reported until an event arrives.
+// Each channel starts inactive, so the activity regained after reconnecting or resuming is reported again.
+func (c *Consumer) markActive(channel *consumerChannel) {
+	if c.options.singleActive && channel.active.CompareAndSwap(false, true) {
+		notifyConsumerActive(c.options.notificationCh, c.queueName)
 	}
 }
 
-func (c *Consumer) handle(delivery amqp.Delivery, mutex *sync.Mutex) {
+// handle dispatches the delivery to its handler. Unless it is added to a batch,
+// the event is settled once it returns, so its stream offset can be saved.
+func (c *Consumer) handle(channel *consumerChannel, delivery amqp.Delivery, batches *batcher) {
+	batched := false
+	defer func() {
+		if !batched {
+			c.offsetSettled(batches.offsets, delivery)
+		}
+	}()
+
 	startTime := time.Now()
 	deliveryInfo := getDeliveryInfo(c.queueName, delivery)
 	eventReceived(c.queueName, deliveryInfo.RoutingKey)
 
 	// Establish which handler is invoked
-	mutex.Lock()
-	handler, ok := c.options.handlers[deliveryInfo.RoutingKey]
-	mutex.Unlock()
-	if !ok {
+	c.handlersMu.RLock()
+	handler, raw, ok := c.getHandler(deliveryInfo.RoutingKey, delivery.Headers)
+	batchKey, batch, isBatch := c.getBatchHandler(deliveryInfo.RoutingKey)
+	c.handlersMu.RUnlock()
+	if !ok && !isBatch {
 		if c.options.defaultHandler == nil {
-			_ = delivery.Nack(false, false)
-			notifyEventHandlerNotFound(c.options.notificationCh, deliveryInfo.RoutingKey)
+			notifyEventHandlerNotFound(c.options.notificationCh, deliveryInfo.RoutingKey, delivery.MessageId, bodyPreview(delivery.Body))
+			c.park(channel, delivery, deliveryInfo, parkReasonHandlerNotFound, nil)
 			eventWithoutHandler(c.queueName, deliveryInfo.RoutingKey)
 			return
 		}
 		handler = c.options.defaultHandler
 	}
 
-	uevt := unmarshalEvent{DeliveryInfo: deliveryInfo}
+	uevt, err := decodeEvent(delivery, deliveryInfo, raw || c.options.rawEvents)
 
 	// For this error to happen an event not published by Bunnify is required
-	if err := json.Unmarshal(delivery.Body, &uevt); err != nil {
-		_ = delivery.Nack(false, false)
+	if err != nil {
+		notifyEventNotParsable(c.options.notificationCh, deliveryInfo.RoutingKey, delivery.MessageId, bodyPreview(delivery.Body), err)
+		c.park(channel, delivery, deliveryInfo, parkReasonNotParsable, err)
 		eventNotParsable(c.queueName, deliveryInfo.RoutingKey)
 		return
 	}
 
-	tracingCtx := extractToContext(delivery.Headers)
-	if err := handler(tracingCtx, uevt); err != nil {
-		elapsed := time.Since(startTime).Milliseconds()
-		notifyEventHandlerFailed(c.options.notificationCh, deliveryInfo.RoutingKey, elapsed, err)
-		_ = delivery.Nack(false, c.shouldRetry(delivery.Headers))
-		eventNack(c.queueName, deliveryInfo.RoutingKey, elapsed)
+	if c.skipDuplicate(delivery, deliveryInfo, uevt.ID) {
 		return
 	}
 
-	elapsed := time.Since(startTime).Milliseconds()
-	notifyEventHandlerSucceed(c.options.notificationCh, deliveryInfo.RoutingKey, elapsed)
-	_ = delivery.Ack(false)
-	eventAck(c.queueName, deliveryInfo.RoutingKey, elapsed)
+	if isBatch {
+		batches.add(batchKey, batch, batchItem{
+			delivery:     delivery,
+			deliveryInfo: deliveryInfo,
+			event:        uevt,
+			startTime:    startTime,
+		})
+		batched = true
+		return
+	}
+
+	c.inFlight.Add(1)
+	defer c.inFlight.Add(-1)
+
+	tracingCtx, span := c.startProcessSpan(delivery, deliveryInfo, uevt)
+	err = handler(tracingCtx, uevt)
+	endSpan(span, err)
+	if err == nil {
+		c.markProcessed(uevt.ID)
+	}
+	c.settle(channel, delivery, deliveryInfo, startTime, err)
 }
 
-func (c *Consumer) shouldRetry(headers amqp.Table) bool {
-	if c.options.retries <= 0 {
-		return false
+// settle acknowledges, NACKs or disposes the event depending on the result of the handler.
+func (c *Consumer) settle(
+	channel *consumerChannel,
+	delivery amqp.Delivery,
+	deliveryInfo DeliveryInfo,
+	startTime time.Time,
+	err error) {
+
+	if streamErr := c.validateStreamResult(err); streamErr != nil {
+		err = streamErr
 	}
 
-	// On RabbitMQ 4+, basic.nack with requeue increments x-acquired-count on the
-	// redelivery. On RabbitMQ 3, the equivalent counter was x-delivery-count.
-	// Before the first retry, neither header is present (so 0).
-	count, ok := headers["x-acquired-count"]
-	if !ok {
-		count, ok = headers["x-delivery-count"]
+	var disposition *DispositionError
+	if errors.As(err, &disposition) {
+		elapsed := time.Since(startTime).Milliseconds()
+		notifyEventHandlerDisposed(c.options.notificationCh, deliveryInfo.RoutingKey, disposition.Disposition, elapsed)
+		c.dispose(channel, delivery, deliveryInfo, disposition)
+		eventDisposed(c.queueName, deliveryInfo.RoutingKey, disposition.Disposition, elapsed)
+		return
 	}
-	if !ok {
-		return true
+
+	if err != nil {
+		c.recordHandlerError(err)
+		elapsed := time.Since(startTime).Milliseconds()
+		notifyEventHandlerFailed(c.options.notificationCh, deliveryInfo.RoutingKey, elapsed, err)
+		c.nack(channel, delivery, deliveryInfo, err)
+		eventNack(c.queueName, deliveryInfo.RoutingKey, classifyError(err), elapsed)
+		return
 	}
 
-	r, _ := count.(int64)
-	return c.options.retries > int(r)
+	elapsed := time.Since(startTime).Milliseconds()
+	notifyEventHandlerSucceed(c.options.notificationCh, deliveryInfo.RoutingKey, elapsed)
+	_ = delivery.Ack(false)
+	eventAck(c.queueName, deliveryInfo.RoutingKey, elapsed)
 }
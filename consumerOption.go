@@ -2,18 +2,87 @@ package bunnify
 
 import (
 	"encoding/json"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ExchangeKind indicates how the exchange routes events to the bound queues.
+type ExchangeKind string
+
+const (
+	ExchangeKindDirect  ExchangeKind = "direct"
+	ExchangeKindTopic   ExchangeKind = "topic"
+	ExchangeKindFanout  ExchangeKind = "fanout"
+	ExchangeKindHeaders ExchangeKind = "headers"
+)
+
+// HeaderMatch indicates if all or any of the headers have to match
+// for an event to be routed to the queue and dispatched to the handler.
+type HeaderMatch string
+
+const (
+	HeaderMatchAll HeaderMatch = "all"
+	HeaderMatchAny HeaderMatch = "any"
+)
+
+// Overflow indicates what happens when a queue reaches its maximum length.
+type Overflow string
+
+const (
+	OverflowDropHead         Overflow = "drop-head"
+	OverflowRejectPublish    Overflow = "reject-publish"
+	OverflowRejectPublishDLX Overflow = "reject-publish-dlx"
 )
 
 type consumerOption struct {
+	name            string
 	deadLetterQueue string
+	failureDetails  bool
+	parkingLotQueue string
+	passive         bool
 	exchange        string
+	exchangeKind    ExchangeKind
 	defaultHandler  wrappedHandler
 	handlers        map[string]wrappedHandler
+	rawHandlers     map[string]struct{}
+	rawEvents       bool
+	headerHandlers  []headerHandler
+	batchHandlers   map[string]batchHandler
 	prefetchCount   int
 	prefetchSize    int
 	quorumQueue     bool
+	streamQueue     bool
+	streamOffset    StreamOffset
+	offsetStore     OffsetStore
+	dedupStore      DedupStore
+	singleActive    bool
+	messageTTL      time.Duration
+	maxLength       int
+	maxLengthBytes  int
+	overflow        Overflow
+	maxPriority     int
+	deliveryLimit   int
+	exclusive       bool
 	notificationCh  chan<- Notification
+	tracerProvider  trace.TracerProvider
+	managementAPI   *managementAPI
 	retries         int
+	retryDelays     []time.Duration
+	retryRepublish  bool
+
+	reconnectBackoff     time.Duration
+	maxReconnectBackoff  time.Duration
+	maxReconnectAttempts int
+}
+
+// WithConsumerName specifies the name that identifies the consumer, on the amqp_consumer_state metric and as the key
+// of the OffsetStore. It should be stable across restarts and unique among the consumers of the same queue, while
+// consumers reading a stream independently need different names. By default, it is the queue name.
+func WithConsumerName(name string) func(*consumerOption) {
+	return func(opt *consumerOption) {
+		opt.name = name
+	}
 }
 
 // WithBindingToExchange specifies the exchange on which the queue
@@ -24,6 +93,16 @@ func WithBindingToExchange(exchange string) func(*consumerOption) {
 	}
 }
 
+// WithExchangeKind specifies the kind of the exchange declared by WithBindingToExchange.
+// If not specified, the exchange is declared as direct. When the kind is topic, the routing keys
+// of the handlers can use the * and # wildcards. Events are dispatched to the handler with the exact
+// routing key first and, if there is none, to the most specific pattern that matches.
+func WithExchangeKind(kind ExchangeKind) func(*consumerOption) {
+	return func(opt *consumerOption) {
+		opt.exchangeKind = kind
+	}
+}
+
 // WithQoS specifies the prefetch count and size for the consumer.
 func WithQoS(prefetchCount, prefetchSize int) func(*consumerOption) {
 	return func(opt *consumerOption) {
@@ -40,8 +119,133 @@ func WithQuorumQueue() func(*consumerOption) {
 	}
 }
 
+// WithMessageTTL specifies for how long an event can stay on the queue before it is
+// discarded or sent to dead letter. It is rounded up to whole milliseconds.
+func WithMessageTTL(ttl time.Duration) func(*consumerOption) {
+	return func(opt *consumerOption) {
+		opt.messageTTL = ttl
+	}
+}
+
+// WithMaxLength specifies the maximum amount of events the queue can hold.
+// What happens when the limit is reached depends on WithOverflow.
+func WithMaxLength(maxLength int) func(*consumerOption) {
+	return func(opt *consumerOption) {
+		opt.maxLength = maxLength
+	}
+}
+
+// WithMaxLengthBytes specifies the maximum total size of the event bodies the queue can hold.
+// What happens when the limit is reached depends on WithOverflow.
+func WithMaxLengthBytes(maxLengthBytes int) func(*consumerOption) {
+	return func(opt *consumerOption) {
+		opt.maxLengthBytes = maxLengthBytes
+	}
+}
+
+// WithOverflow specifies the behavior when the queue reaches its maximum length.
+// If not specified, the server drops the oldest events. Quorum queues do not support reject-publish-dlx.
+func WithOverflow(overflow Overflow) func(*consumerOption) {
+	return func(opt *consumerOption) {
+		opt.overflow = overflow
+	}
+}
+
+// WithMaxPriority specifies that the queue supports event priorities from 0 up to the indicated one.
+// The maximum priority must be between 1 and 255. Quorum queues do not support this argument.
+func WithMaxPriority(maxPriority int) func(*consumerOption) {
+	return func(opt *consumerOption) {
+		opt.maxPriority = maxPriority
+	}
+}
+
+// WithDeliveryLimit specifies how many times an event can be delivered before it is
+// discarded or sent to dead letter. Only quorum queues support this argument.
+func WithDeliveryLimit(deliveryLimit int) func(*consumerOption) {
+	return func(opt *consumerOption) {
+		opt.deliveryLimit = deliveryLimit
+	}
+}
+
+// WithSingleActiveConsumer specifies that the queue will be created with single active consumer enabled.
+// Only one of the consumers of the queue receives events at a time, the rest take over if it goes away.
+// As the server does not inform which consumer is active, a notification is sent when the channel
+// receives its first event, meaning this consumer became the active one. This is a heuristic: being
+// active on an empty queue is not reported, and it is reported again after reconnecting or resuming.
+func WithSingleActiveConsumer() func(*consumerOption) {
+	return func(opt *consumerOption) {
+		opt.singleActive = true
+	}
+}
+
+// WithExclusiveConsumer specifies that the consumer will request exclusive access to the queue.
+// Consume returns an error if the queue already has another consumer.
+func WithExclusiveConsumer() func(*consumerOption) {
+	return func(opt *consumerOption) {
+		opt.exclusive = true
+	}
+}
+
+// WithStreamQueue specifies that the queue to consume will be created as a stream.
+// Streams keep the events after they are consumed, so they can be read again from any offset.
+// Dead letter, retries and most queue arguments are not supported by streams. Handlers cannot
+// requeue, defer nor retry the events either, as the copy would be appended to the stream again,
+// so those are handled as permanent failures. When the consumer reconnects or is resumed, it
+// continues from the event after the last one handled successfully.
+func WithStreamQueue() func(*consumerOption) {
+	return func(opt *consumerOption) {
+		opt.streamQueue = true
+	}
+}
+
+// WithStreamOffset specifies from where the stream is consumed when there is no stored offset
+// and no event was handled yet.
+// If not specified, only the events published after the consumer starts are received.
+func WithStreamOffset(offset StreamOffset) func(*consumerOption) {
+	return func(opt *consumerOption) {
+		opt.streamOffset = offset
+	}
+}
+
+// WithOffsetStore specifies where the offset up to which every event was handled is stored, by consumer name.
+// Events handled in parallel or in batches can finish out of order, so the offset only advances past an event
+// once the previous ones are handled too. When the consumer starts, it resumes from the event after the
+// stored offset, so the progress is kept across restarts of the application and not only across reconnections.
+func WithOffsetStore(store OffsetStore) func(*consumerOption) {
+	return func(opt *consumerOption) {
+		opt.offsetStore = store
+	}
+}
+
+// WithDeduplication specifies a store to keep track of the events handled successfully.
+// Events which ID was already handled are acknowledged without invoking the handler.
+// Events handled in parallel can still be processed twice if the duplicate arrives while the first is in flight.
+func WithDeduplication(store DedupStore) func(*consumerOption) {
+	return func(opt *consumerOption) {
+		opt.dedupStore = store
+	}
+}
+
+// WithReconnectBackoff specifies how much time to wait before consuming again after
+// the channel was lost and consuming could not start. The wait doubles after every
+// attempt up to the maximum. By default it starts at 100 milliseconds up to 10 seconds.
+func WithReconnectBackoff(initial, maximum time.Duration) func(*consumerOption) {
+	return func(opt *consumerOption) {
+		opt.reconnectBackoff = initial
+		opt.maxReconnectBackoff = maximum
+	}
+}
+
+// WithMaxReconnectAttempts specifies how many times to try consuming again after the channel
+// was lost before the consumer fails. By default, it keeps trying until the connection is closed.
+func WithMaxReconnectAttempts(attempts int) func(*consumerOption) {
+	return func(opt *consumerOption) {
+		opt.maxReconnectAttempts = attempts
+	}
+}
+
 // WithRetries specifies the retries count before the event is discarded or sent to dead letter.
-// Quorum queues are required to use this feature.
+// Quorum queues are required to use this feature unless WithRetryDelays or WithRetriesByRepublish are used.
 // The event will be processed at max as retries + 1.
 // If specified amount is 3, the event can be processed up to 4 times.
 func WithRetries(retries int) func(*consumerOption) {
@@ -50,6 +254,29 @@ func WithRetries(retries int) func(*consumerOption) {
 	}
 }
 
+// WithRetryDelays specifies the delays to wait before each retry of a failed event.
+// Instead of requeueing the event, it is published to a delay queue which sends it
+// back to the consumer queue once the delay expires, then the original is acknowledged.
+// If WithRetries is not used, the event is retried once per delay. If the retries are more
+// than the delays, the last delay is used for the remaining ones.
+// The delays are rounded up to whole milliseconds. Quorum queues are not required to use this feature.
+func WithRetryDelays(delays ...time.Duration) func(*consumerOption) {
+	return func(opt *consumerOption) {
+		opt.retryDelays = delays
+	}
+}
+
+// WithRetriesByRepublish specifies that failed events are retried by publishing
+// a copy back to the consumer queue with a retry count header, then the original is acknowledged.
+// As the count is tracked by bunnify instead of the broker, this works with any queue type
+// and behaves the same regardless of the RabbitMQ version. Retried events go to the back of the queue.
+// It has to be used together with WithRetries, otherwise Consume returns an error.
+func WithRetriesByRepublish() func(*consumerOption) {
+	return func(opt *consumerOption) {
+		opt.retryRepublish = true
+	}
+}
+
 // WithDeadLetterQueue indicates which queue will receive the events
 // that were NACKed for this consumer.
 func WithDeadLetterQueue(queueName string) func(*consumerOption) {
@@ -58,6 +285,45 @@ func WithDeadLetterQueue(queueName string) func(*consumerOption) {
 	}
 }
 
+// WithFailureDetails specifies that failed events are published to the dead letter queue
+// by bunnify instead of NACKed, adding headers with the error message, error type,
+// handler routing key, attempts and failure time. Then the original is acknowledged.
+// It has to be used together with WithDeadLetterQueue.
+func WithFailureDetails() func(*consumerOption) {
+	return func(opt *consumerOption) {
+		opt.failureDetails = true
+	}
+}
+
+// WithParkingLotQueue indicates which queue will receive the events that could not be
+// parsed or that have no handler. Events are kept byte for byte and annotated with
+// the reason they were parked. Without it, these events are NACKed without requeue.
+func WithParkingLotQueue(queueName string) func(*consumerOption) {
+	return func(opt *consumerOption) {
+		opt.parkingLotQueue = queueName
+	}
+}
+
+// WithRawEvents specifies that all the events of this consumer were not published by bunnify,
+// so they are not wrapped in the bunnify envelope. The whole body is unmarshalled as the payload
+// and the metadata is taken from the message id, correlation id and timestamp properties.
+func WithRawEvents() func(*consumerOption) {
+	return func(opt *consumerOption) {
+		opt.rawEvents = true
+	}
+}
+
+// WithPassiveDeclarations specifies that the exchanges and queues are not declared but only verified
+// with passive declarations, for when the application is not allowed to declare entities.
+// Consume returns a *TopologyMismatchError listing the ones that do not exist, or whose arguments
+// differ when WithManagementAPI is supplied on the connection. Bindings are not created either,
+// as they are expected to be managed together with the rest of the topology.
+func WithPassiveDeclarations() func(*consumerOption) {
+	return func(opt *consumerOption) {
+		opt.passive = true
+	}
+}
+
 // WithDefaultHandler specifies a handler that can be use for any type
 // of routing key without a defined handler. This is mostly convenient if you
 // don't care about the specific payload of the event, which will be received as a byte array.
@@ -72,5 +338,48 @@ func WithDefaultHandler(handler EventHandler[json.RawMessage]) func(*consumerOpt
 func WithHandler[T any](routingKey string, handler EventHandler[T]) func(*consumerOption) {
 	return func(opt *consumerOption) {
 		opt.handlers[routingKey] = newWrappedHandler(handler)
+		delete(opt.rawHandlers, routingKey)
+	}
+}
+
+// WithRawHandler specifies under which routing key the provided handler will be invoked
+// for events not published by bunnify. The whole body is unmarshalled as the payload
+// and the metadata is taken from the message id, correlation id and timestamp properties.
+// The routing key indicated here will be bound to the queue if the WithBindingToExchange is supplied.
+func WithRawHandler[T any](routingKey string, handler EventHandler[T]) func(*consumerOption) {
+	return func(opt *consumerOption) {
+		opt.handlers[routingKey] = newWrappedHandler(handler)
+		opt.rawHandlers[routingKey] = struct{}{}
+	}
+}
+
+// WithHeaderHandler specifies that the provided handler will be invoked for the events
+// which headers match all or any of the given ones. Handlers registered for the routing key
+// take precedence, then the header handlers are checked in the order they were added.
+// If the exchange kind is headers, the queue will be bound to the exchange with these headers.
+func WithHeaderHandler[T any](match HeaderMatch, headers map[string]any, handler EventHandler[T]) func(*consumerOption) {
+	return func(opt *consumerOption) {
+		opt.headerHandlers = append(opt.headerHandlers, headerHandler{
+			match:   match,
+			headers: headers,
+			handler: newWrappedHandler(handler),
+		})
+	}
+}
+
+// WithBatchHandler specifies under which routing key the provided batch handler will be invoked.
+// Events are accumulated until the size is reached or maxWait passes since the first one arrived,
+// then the handler is invoked once with all of them. A successful batch is acknowledged at once when possible.
+// The size should not be greater than the prefetch count, as the batch would never be filled.
+// When consuming from a topic exchange, the routing key can be a pattern, matched the same way as
+// for WithHandler, and the events matching it are accumulated in the same batch.
+// The routing key indicated here will be bound to the queue if the WithBindingToExchange is supplied.
+func WithBatchHandler[T any](routingKey string, size int, maxWait time.Duration, handler BatchEventHandler[T]) func(*consumerOption) {
+	return func(opt *consumerOption) {
+		opt.batchHandlers[routingKey] = batchHandler{
+			size:    size,
+			maxWait: maxWait,
+			handler: newWrappedBatchHandler(handler),
+		}
 	}
 }
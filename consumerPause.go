@@ -0,0 +1,124 @@
+package bunnify
+
+import (
+	"fmt"
+)
+
+// Pause stops receiving events from the queue without closing the connection.
+// The events already received are handled before returning, so once paused
+// there are no handlers running. The consumer keeps its handlers and bindings.
+// It must not be called from a handler, as it would wait for that handler to return.
+func (c *Consumer) Pause() error {
+	if err := c.cancel(false); err != nil {
+		return err
+	}
+
+	notifyConsumerPaused(c.options.notificationCh, c.queueName)
+	return nil
+}
+
+// cancel stops receiving events and waits for the ones already received to be handled.
+// When stopping, the consumer ends in the stopped state instead of paused and cannot be resumed.
+func (c *Consumer) cancel(stop bool) error {
+	c.mu.Lock()
+	if stop {
+		c.stopping = true
+	}
+	if c.paused {
+		c.mu.Unlock()
+		if stop {
+			c.finish(ConsumerStateStopped, nil)
+		}
+		return nil
+	}
+	if c.state != ConsumerStateConsuming && c.state != ConsumerStateReconnecting {
+		c.mu.Unlock()
+		return fmt.Errorf("consumer is not consuming")
+	}
+	c.paused = true
+	if c.stop != nil {
+		close(c.stop)
+	}
+	channel, consumerTag, drained := c.channel, c.consumerTag, c.drained
+	c.mu.Unlock()
+
+	// If the channel is closed, the consumer stops reconnecting on the next attempt
+	if channel != nil {
+		if err := channel.Cancel(consumerTag, false); err != nil && !channel.IsClosed() {
+			return fmt.Errorf("failed to cancel consuming from queue: %w", err)
+		}
+	}
+
+	if drained != nil {
+		<-drained
+	}
+	return nil
+}
+
+// Resume starts receiving events again after the consumer was paused,
+// using the same handlers and bindings, either sequentially or in parallel as before.
+func (c *Consumer) Resume() error {
+	c.mu.Lock()
+	if c.stopping {
+		c.mu.Unlock()
+		return fmt.Errorf("consumer is stopped")
+	}
+	if !c.paused {
+		c.mu.Unlock()
+		return nil
+	}
+	c.paused = false
+	c.stop = make(chan struct{})
+	parallel := c.parallel
+	c.mu.Unlock()
+
+	// If consuming cannot start, the consumer stays paused so it can be resumed again
+	channel, deliveries, err := c.start(parallel)
+	if err != nil {
+		c.mu.Lock()
+		c.paused = true
+		c.mu.Unlock()
+		return err
+	}
+
+	c.setState(ConsumerStateConsuming, nil)
+	go c.supervise(channel, deliveries, parallel)
+
+	notifyConsumerResumed(c.options.notificationCh, c.queueName)
+	return nil
+}
+
+// isPaused returns true if the consumer was paused.
+func (c *Consumer) isPaused() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.paused
+}
+
+// pausedState returns the state once the consumer stopped receiving events after being paused.
+func (c *Consumer) pausedState() ConsumerState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stopping {
+		return ConsumerStateStopped
+	}
+	return ConsumerStatePaused
+}
+
+// stopSignal returns a go channel that is closed once the consumer is paused or stopped,
+// to stop waiting for a channel or for the reconnection backoff.
+func (c *Consumer) stopSignal() <-chan struct{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stop
+}
+
+// markDrained signals that the loop stopped after the consumer was paused.
+func (c *Consumer) markDrained() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.drained != nil {
+		close(c.drained)
+		c.drained = nil
+	}
+}
@@ -0,0 +1,34 @@
+package bunnify
+
+import "testing"
+
+func TestPauseResume(t *testing.T) {
+	t.Run("When consumer is not consuming it cannot be paused", func(t *testing.T) {
+		// Setup
+		consumer := Consumer{&consumerCore{queueName: "queue"}}
+
+		// Exercise
+		err := consumer.Pause()
+
+		// Assert
+		if err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("When consumer is not paused resume does nothing", func(t *testing.T) {
+		// Setup
+		consumer := Consumer{&consumerCore{queueName: "queue"}}
+
+		// Exercise
+		err := consumer.Resume()
+
+		// Assert
+		if err != nil {
+			t.Fatal(err)
+		}
+		if consumer.isPaused() {
+			t.Fatal("expected consumer not to be paused")
+		}
+	})
+}
@@ -0,0 +1,299 @@
+package bunnify
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+const (
+	retryCountHeader         = "x-bunnify-retry-count"
+	originalExchangeHeader   = "x-bunnify-original-exchange"
+	originalRoutingKeyHeader = "x-bunnify-original-routing-key"
+	originalQueueHeader      = "x-bunnify-original-queue"
+)
+
+// retryQueueName returns the name of the delay queue used for the given attempt.
+// When there are more retries than delays, the last delay is reused.
+func (c *Consumer) retryQueueName(attempt int) string {
+	delays := c.options.retryDelays
+	delay := delays[min(attempt, len(delays)-1)]
+	return fmt.Sprintf("%s-retry-%s", c.queueName, delay)
+}
+
+// maxRetries returns how many times a failed event can be retried.
+// If retries were not specified but delays were, there is one retry per delay.
+func (c *Consumer) maxRetries() int {
+	if c.options.retries > 0 {
+		return c.options.retries
+	}
+	return len(c.options.retryDelays)
+}
+
+// retriesByRepublish returns true when retries are tracked by bunnify
+// with a header instead of relying on the broker delivery count.
+func (c *Consumer) retriesByRepublish() bool {
+	return c.options.retryRepublish || len(c.options.retryDelays) > 0
+}
+
+// createRetryQueues declares one queue per retry delay. Events published to these
+// queues expire after the delay and get dead lettered back to the consumer queue.
+func (c *Consumer) createRetryQueues(channel *amqp.Channel) error {
+	for _, delay := range c.options.retryDelays {
+		if delay <= 0 {
+			return fmt.Errorf("retry delays must be positive, got %s", delay)
+		}
+	}
+
+	for attempt := range c.options.retryDelays {
+		_, err := channel.QueueDeclare(
+			c.retryQueueName(attempt),
+			true,  // durable
+			false, // auto-delete
+			false, // exclusive
+			false, // no-wait
+			c.retryQueueArguments(attempt),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// retryQueueArguments returns the arguments used to declare the delay queue of the given attempt.
+func (c *Consumer) retryQueueArguments(attempt int) amqp.Table {
+	amqpTable := amqp.Table{
+		"x-message-ttl":             ttlMilliseconds(c.options.retryDelays[attempt]),
+		"x-dead-letter-exchange":    "",
+		"x-dead-letter-routing-key": c.queueName,
+	}
+
+	if c.options.quorumQueue {
+		amqpTable["x-queue-type"] = "quorum"
+	}
+
+	return amqpTable
+}
+
+// nack handles an event which handler failed. Permanent errors are sent to dead letter straight away.
+// Retryable errors with a duration are republished to the deferred queue. Otherwise, when retries
+// are tracked by bunnify, the event is republished either to the consumer queue or to the matching
+// delay queue and the original is acknowledged, if not it is NACKed and requeued depending on the retries left.
+func (c *Consumer) nack(channel *consumerChannel, delivery amqp.Delivery, deliveryInfo DeliveryInfo, err error) {
+	var permanent *PermanentError
+	if errors.As(err, &permanent) || !c.shouldRetry(delivery.Headers) {
+		c.deadLetter(channel, delivery, deliveryInfo, err)
+		return
+	}
+
+	attempt := attempts(delivery.Headers)
+
+	var retryable *RetryableError
+	if errors.As(err, &retryable) && retryable.After > 0 {
+		c.republishDeferred(channel, delivery, deliveryInfo, attempt+1, retryable.After)
+		return
+	}
+
+	if !c.retriesByRepublish() {
+		_ = delivery.Nack(false, true)
+		return
+	}
+
+	queueName := c.queueName
+	if len(c.options.retryDelays) > 0 {
+		queueName = c.retryQueueName(attempt)
+	}
+
+	c.republishOrRequeue(channel, delivery, deliveryInfo, queueName, attempt+1, "")
+}
+
+// dispose applies the disposition chosen by the handler. Requeued and deferred
+// events are republished keeping the current retry count, so they do not count as a retry.
+func (c *Consumer) dispose(
+	channel *consumerChannel,
+	delivery amqp.Delivery,
+	deliveryInfo DeliveryInfo,
+	disposition *DispositionError) {
+
+	switch disposition.Disposition {
+	case DispositionRequeue:
+		c.republishOrRequeue(channel, delivery, deliveryInfo, c.queueName, attempts(delivery.Headers), "")
+	case DispositionDefer:
+		c.republishDeferred(channel, delivery, deliveryInfo, attempts(delivery.Headers), disposition.After)
+	default:
+		c.deadLetter(channel, delivery, deliveryInfo, disposition)
+	}
+}
+
+// ensureDeferredQueue declares the queue used to process events again after a delay, with
+// bunnify.Defer or bunnify.Retryable, the first time an event is deferred. It is not declared with
+// the other queues, as most consumers never defer events. With passive declarations it is only verified.
+// A separate channel is used, so the consuming one is not closed if it exists with other arguments.
+func (c *Consumer) ensureDeferredQueue() error {
+	if c.deferredQueueReady.Load() {
+		return nil
+	}
+
+	steps := []topologyStep{QueueDefinition{
+		Name:      c.deferredQueueName(),
+		Durable:   true,
+		Arguments: c.deferredQueueArguments(),
+	}.step()}
+
+	var err error
+	if c.options.passive {
+		err = c.verifyTopology(steps)
+	} else {
+		runTopology(steps, false, c.newTopologyChannel, func(step topologyStep, stepErr error) {
+			err = fmt.Errorf("failed to declare %s: %w", step.entity, stepErr)
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	c.deferredQueueReady.Store(true)
+	return nil
+}
+
+// republishDeferred republishes the event to the deferred queue with a per message TTL.
+// Once expired, the event is dead lettered back to the consumer queue.
+// As the TTL is only checked for the event at the head of the deferred queue,
+// the event can wait longer than requested if it is behind one with a longer TTL.
+func (c *Consumer) republishDeferred(
+	channel *consumerChannel,
+	delivery amqp.Delivery,
+	deliveryInfo DeliveryInfo,
+	retryCount int,
+	after time.Duration) {
+
+	if err := c.ensureDeferredQueue(); err != nil {
+		notifyEventRepublishFailed(c.options.notificationCh, deliveryInfo.RoutingKey, err)
+		_ = delivery.Nack(false, true)
+		return
+	}
+
+	expiration := strconv.FormatInt(ttlMilliseconds(after), 10)
+	c.republishOrRequeue(channel, delivery, deliveryInfo, c.deferredQueueName(), retryCount, expiration)
+}
+
+// deferredQueueName returns the name of the queue used for deferred events.
+func (c *Consumer) deferredQueueName() string {
+	return fmt.Sprintf("%s-deferred", c.queueName)
+}
+
+// deferredQueueArguments returns the arguments used to declare the deferred queue.
+func (c *Consumer) deferredQueueArguments() amqp.Table {
+	amqpTable := amqp.Table{
+		"x-dead-letter-exchange":    "",
+		"x-dead-letter-routing-key": c.queueName,
+	}
+
+	if c.options.quorumQueue {
+		amqpTable["x-queue-type"] = "quorum"
+	}
+
+	return amqpTable
+}
+
+// republishOrRequeue republishes the event to the given queue and acknowledges the original once
+// the server confirms the copy. If the republish fails, the original is requeued so that the event is not lost.
+func (c *Consumer) republishOrRequeue(
+	channel *consumerChannel,
+	delivery amqp.Delivery,
+	deliveryInfo DeliveryInfo,
+	queueName string,
+	retryCount int,
+	expiration string) {
+
+	headers := retryHeaders(delivery, deliveryInfo, retryCount)
+	err := channel.republish("", queueName, delivery, headers, expiration)
+	if err != nil {
+		notifyEventRepublishFailed(c.options.notificationCh, deliveryInfo.RoutingKey, err)
+		_ = delivery.Nack(false, true)
+		return
+	}
+
+	_ = delivery.Ack(false)
+}
+
+// shouldRetry returns true if the event has retries left.
+func (c *Consumer) shouldRetry(headers amqp.Table) bool {
+	return c.maxRetries() > attempts(headers)
+}
+
+// retryHeaders returns the headers of the delivery with the retry count. The original queue,
+// exchange and routing key are kept as well, so the handler can be resolved once the event is consumed again.
+// The headers set by the broker are not copied, as the redeliveries they count are already part of the retry count.
+func retryHeaders(delivery amqp.Delivery, deliveryInfo DeliveryInfo, retryCount int) amqp.Table {
+	headers := amqp.Table{}
+	for k, v := range delivery.Headers {
+		switch k {
+		case "x-death", "x-delivery-count", "x-acquired-count":
+		default:
+			headers[k] = v
+		}
+	}
+	headers[retryCountHeader] = int64(retryCount)
+	headers[originalQueueHeader] = deliveryInfo.Queue
+	headers[originalExchangeHeader] = deliveryInfo.Exchange
+	headers[originalRoutingKeyHeader] = deliveryInfo.RoutingKey
+	return headers
+}
+
+// attempts returns how many times the event was already retried, either
+// by bunnify republishing it or by the broker redelivering it.
+func attempts(headers amqp.Table) int {
+	return retryCount(headers) + deliveryCount(headers)
+}
+
+// retryCount returns how many times bunnify already retried the event.
+func retryCount(headers amqp.Table) int {
+	count, _ := headerInt(headers, retryCountHeader)
+	return int(count)
+}
+
+// deliveryCount returns how many times the broker redelivered the event after a NACK.
+// On RabbitMQ 4+, basic.nack with requeue increments x-acquired-count on the
+// redelivery. On RabbitMQ 3, the equivalent counter was x-delivery-count.
+// Before the first retry, neither header is present (so 0).
+func deliveryCount(headers amqp.Table) int {
+	count, ok := headerInt(headers, "x-acquired-count")
+	if !ok {
+		count, _ = headerInt(headers, "x-delivery-count")
+	}
+	return int(count)
+}
+
+// headerInt reads an integer header regardless of the width it was encoded with.
+func headerInt(headers amqp.Table, key string) (int64, bool) {
+	return intValue(headers[key])
+}
+
+func intValue(value any) (int64, bool) {
+	switch v := value.(type) {
+	case int:
+		return int64(v), true
+	case int8:
+		return int64(v), true
+	case int16:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case uint8:
+		return int64(v), true
+	case uint16:
+		return int64(v), true
+	case uint32:
+		return int64(v), true
+	default:
+		return 0, false
+	}
+}
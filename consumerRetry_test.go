@@ -0,0 +1,43 @@
+package bunnify
+
+import (
+	"testing"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+func TestRetryHeaders(t *testing.T) {
+	t.Run("When the event is republished twice the broker counters are not added again", func(t *testing.T) {
+		// Setup
+		deliveryInfo := DeliveryInfo{Queue: "orders", Exchange: "events", RoutingKey: "order.created"}
+		delivery := amqp.Delivery{Headers: amqp.Table{
+			retryCountHeader:   int64(1),
+			"x-acquired-count": int64(2),
+			"x-delivery-count": int64(2),
+			"traceparent":      "00-trace-span-01",
+		}}
+
+		// Exercise, retrying the event and then requeueing it without counting a retry
+		retried := amqp.Delivery{Headers: retryHeaders(delivery, deliveryInfo, attempts(delivery.Headers)+1)}
+		requeued := amqp.Delivery{Headers: retryHeaders(retried, deliveryInfo, attempts(retried.Headers))}
+
+		// Assert
+		if n := attempts(retried.Headers); n != 4 {
+			t.Fatalf("expected 4 attempts after retrying, got %d", n)
+		}
+		if n := attempts(requeued.Headers); n != 4 {
+			t.Fatalf("expected 4 attempts after requeueing, got %d", n)
+		}
+		if n := retryCount(requeued.Headers); n != 4 {
+			t.Fatalf("expected retry count 4, got %d", n)
+		}
+		for _, header := range []string{"x-acquired-count", "x-delivery-count"} {
+			if _, ok := requeued.Headers[header]; ok {
+				t.Fatalf("expected %s not to be copied", header)
+			}
+		}
+		if requeued.Headers["traceparent"] != "00-trace-span-01" {
+			t.Fatalf("expected the other headers to be copied, got %v", requeued.Headers)
+		}
+	})
+}
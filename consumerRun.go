@@ -0,0 +1,48 @@
+package bunnify
+
+import "context"
+
+// Run consumes events from the indicated queue and blocks until the context is cancelled
+// or the consumer fails. When the context is cancelled, it stops receiving events and waits
+// for the ones already received to be handled, then returns nil. It also returns nil if the
+// connection is closed. Otherwise, it returns the error that made the consumer fail,
+// so it can be used with an errgroup to tie the consumer to the lifetime of the application.
+func (c *Consumer) Run(ctx context.Context) error {
+	return c.run(ctx, false)
+}
+
+// RunParallel is the same as Run, but it fires a go routine per each message
+// received as opposed of sequentially, like ConsumeParallel.
+func (c *Consumer) RunParallel(ctx context.Context) error {
+	return c.run(ctx, true)
+}
+
+func (c *Consumer) run(ctx context.Context, parallel bool) error {
+	if err := c.consume(parallel); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	done := c.done
+	c.mu.Unlock()
+
+	if done == nil {
+		return c.Err()
+	}
+
+	select {
+	case <-ctx.Done():
+		err := c.cancel(true)
+		if err == nil || c.State() == ConsumerStateStopped {
+			return nil
+		}
+
+		// The consumer failed while the context was being cancelled
+		if failure := c.Err(); failure != nil {
+			return failure
+		}
+		return err
+	case <-done:
+		return c.Err()
+	}
+}
@@ -0,0 +1,96 @@
+package bunnify
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+func TestRun(t *testing.T) {
+	t.Run("When consuming cannot start the error is returned", func(t *testing.T) {
+		// Setup
+		consumer := Consumer{&consumerCore{
+			queueName: "queue",
+			getNewChannel: func(stop <-chan struct{}) (*amqp.Channel, bool) {
+				return nil, true
+			},
+		}}
+
+		// Exercise
+		err := consumer.Run(context.TODO())
+
+		// Assert
+		if !errors.Is(err, errConnectionClosedByUser) {
+			t.Fatalf("expected connection closed error, got %v", err)
+		}
+		if consumer.State() != ConsumerStateFailed {
+			t.Fatalf("expected state %s, got %s", ConsumerStateFailed, consumer.State())
+		}
+	})
+
+	t.Run("When paused consumer is stopped", func(t *testing.T) {
+		// Setup
+		done := make(chan struct{})
+		consumer := Consumer{&consumerCore{queueName: "queue", paused: true, done: done}}
+		consumer.setState(ConsumerStatePaused, nil)
+
+		// Exercise
+		err := consumer.cancel(true)
+
+		// Assert
+		if err != nil {
+			t.Fatal(err)
+		}
+		if consumer.State() != ConsumerStateStopped {
+			t.Fatalf("expected state %s, got %s", ConsumerStateStopped, consumer.State())
+		}
+		select {
+		case <-done:
+		default:
+			t.Fatal("expected consumer to be done")
+		}
+		if err := consumer.Resume(); err == nil {
+			t.Fatal("expected stopped consumer not to resume")
+		}
+	})
+	t.Run("When stopped while reconnecting it does not wait for a channel", func(t *testing.T) {
+		// Setup
+		obtaining := make(chan struct{})
+		consumer := Consumer{&consumerCore{
+			queueName: "queue",
+			done:      make(chan struct{}),
+			stop:      make(chan struct{}),
+			drained:   make(chan struct{}),
+			getNewChannel: func(stop <-chan struct{}) (*amqp.Channel, bool) {
+				// The connection is down, so a channel is obtained only when stopping
+				close(obtaining)
+				<-stop
+				return nil, false
+			},
+		}}
+		consumer.setState(ConsumerStateConsuming, nil)
+
+		restarted := make(chan bool)
+		go func() {
+			_, _, ok := consumer.restart(false)
+			restarted <- ok
+		}()
+		<-obtaining
+
+		// Exercise
+		err := consumer.cancel(true)
+
+		// Assert
+		if err != nil {
+			t.Fatal(err)
+		}
+		if <-restarted {
+			t.Fatal("expected the consumer not to consume again")
+		}
+		if consumer.State() != ConsumerStateStopped {
+			t.Fatalf("expected state %s, got %s", ConsumerStateStopped, consumer.State())
+		}
+	})
+}
@@ -0,0 +1,191 @@
+package bunnify
+
+import (
+	"errors"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// ConsumerState is the state of a consumer.
+type ConsumerState string
+
+const (
+	// ConsumerStateIdle is the state before Consume or ConsumeParallel is called.
+	ConsumerStateIdle ConsumerState = "idle"
+	// ConsumerStateStarting is the state while the topology is declared and consuming starts.
+	ConsumerStateStarting ConsumerState = "starting"
+	// ConsumerStateConsuming is the state while events are received.
+	ConsumerStateConsuming ConsumerState = "consuming"
+	// ConsumerStateReconnecting is the state after the channel was lost, until consuming again.
+	ConsumerStateReconnecting ConsumerState = "reconnecting"
+	// ConsumerStatePaused is the state after Pause, until Resume.
+	ConsumerStatePaused ConsumerState = "paused"
+	// ConsumerStateStopped is the state after the connection was closed.
+	ConsumerStateStopped ConsumerState = "stopped"
+	// ConsumerStateFailed is the state after an error the consumer cannot recover from.
+	ConsumerStateFailed ConsumerState = "failed"
+)
+
+// StateChange describes a transition between two states of a consumer.
+// Err is the cause of the transition to the failed state.
+type StateChange struct {
+	Queue string
+	From  ConsumerState
+	To    ConsumerState
+	Err   error
+}
+
+// State returns the current state of the consumer.
+func (c *Consumer) State() ConsumerState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.state == "" {
+		return ConsumerStateIdle
+	}
+	return c.state
+}
+
+// Err returns the error that made the consumer fail, if any.
+func (c *Consumer) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lastErr
+}
+
+// NotifyStateChange registers a go channel to receive the state transitions of the consumer.
+// The transitions are sent in order without waiting for them to be received, so the consumer
+// is never blocked by a listener. A transition is dropped if the channel is full, so it should
+// be buffered and read continuously.
+func (c *Consumer) NotifyStateChange(ch chan<- StateChange) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.listeners = append(c.listeners, ch)
+}
+
+// setState transitions the consumer to the given state and notifies the listeners.
+func (c *Consumer) setState(state ConsumerState, err error) {
+	c.mu.Lock()
+	from := c.state
+	if from == "" {
+		from = ConsumerStateIdle
+	}
+	c.state = state
+	if state == ConsumerStateFailed {
+		c.lastErr = err
+	}
+	defer c.mu.Unlock()
+
+	if from == state {
+		return
+	}
+	consumerStateChanged(c.queueName, c.options.name, string(state))
+
+	// Sent under the lock so the transitions keep their order, without blocking as the
+	// transition can happen on the supervisor or on Pause and Stop
+	change := StateChange{Queue: c.queueName, From: from, To: state, Err: err}
+	for _, ch := range c.listeners {
+		select {
+		case ch <- change:
+		default:
+		}
+	}
+}
+
+// finish transitions the consumer to the given state and, if the consumer cannot
+// consume anymore as it is stopped or failed, signals it to whoever is running it.
+func (c *Consumer) finish(state ConsumerState, err error) {
+	c.setState(state, err)
+	if state != ConsumerStateStopped && state != ConsumerStateFailed {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.done != nil {
+		close(c.done)
+		c.done = nil
+	}
+}
+
+// supervise handles the deliveries and, once the channel stops, consumes again unless
+// the consumer was paused or the connection was closed. Each time consuming cannot start
+// it waits for the backoff, which doubles every attempt up to the maximum.
+func (c *Consumer) supervise(channel *consumerChannel, deliveries <-chan amqp.Delivery, parallel bool) {
+	for {
+		if parallel {
+			c.parallelLoop(channel, deliveries)
+		} else {
+			c.loop(channel, deliveries)
+		}
+
+		var ok bool
+		if channel, deliveries, ok = c.restart(parallel); !ok {
+			return
+		}
+	}
+}
+
+// restart consumes again once the loop stopped. It returns false when the consumer
+// ended instead, because it was paused, the connection was closed or it failed.
+func (c *Consumer) restart(parallel bool) (*consumerChannel, <-chan amqp.Delivery, bool) {
+	if c.isPaused() {
+		c.finish(c.pausedState(), nil)
+		c.markDrained()
+		return nil, nil, false
+	}
+
+	c.setState(ConsumerStateReconnecting, nil)
+
+	channel, deliveries, err := c.reconnect(parallel)
+	switch {
+	case errors.Is(err, errConnectionClosedByUser):
+		c.finish(ConsumerStateStopped, nil)
+		return nil, nil, false
+	case errors.Is(err, errConsumerPaused):
+		c.finish(c.pausedState(), nil)
+		c.markDrained()
+		return nil, nil, false
+	case err != nil:
+		c.finish(ConsumerStateFailed, err)
+		return nil, nil, false
+	}
+
+	c.setState(ConsumerStateConsuming, nil)
+	return channel, deliveries, true
+}
+
+// reconnect tries to consume again until it succeeds, the consumer is paused, the connection
+// is closed or the maximum attempts are reached, if any. The channel is notified as lost once,
+// then every failed attempt is notified, and finally the channel is notified as restored.
+func (c *Consumer) reconnect(parallel bool) (*consumerChannel, <-chan amqp.Delivery, error) {
+	stop := c.stopSignal()
+	backoff := c.options.reconnectBackoff
+	notifyChannelLost(c.options.notificationCh, NotificationSourceConsumer)
+	for attempt := 1; ; attempt++ {
+		channel, deliveries, err := c.start(parallel)
+		if errors.Is(err, errConnectionClosedByUser) || errors.Is(err, errConsumerPaused) {
+			return nil, nil, err
+		}
+
+		if err == nil {
+			notifyChannelRestored(c.options.notificationCh, NotificationSourceConsumer, attempt)
+			return channel, deliveries, nil
+		}
+
+		notifyChannelFailed(c.options.notificationCh, NotificationSourceConsumer, err)
+		if c.options.maxReconnectAttempts > 0 && attempt >= c.options.maxReconnectAttempts {
+			return nil, nil, err
+		}
+
+		// Pausing or stopping does not wait for the backoff
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-stop:
+			timer.Stop()
+			return nil, nil, errConsumerPaused
+		}
+		backoff = min(backoff*2, c.options.maxReconnectBackoff)
+	}
+}
@@ -0,0 +1,96 @@
+package bunnify
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestConsumerState(t *testing.T) {
+	t.Run("When consumer did not start it is idle", func(t *testing.T) {
+		// Setup
+		consumer := Consumer{&consumerCore{queueName: "queue"}}
+
+		// Exercise
+		state := consumer.State()
+
+		// Assert
+		if state != ConsumerStateIdle {
+			t.Fatalf("expected state %s, got %s", ConsumerStateIdle, state)
+		}
+	})
+
+	t.Run("When state changes listeners are notified", func(t *testing.T) {
+		// Setup
+		consumer := Consumer{&consumerCore{queueName: "queue"}}
+		ch := make(chan StateChange, 3)
+		consumer.NotifyStateChange(ch)
+		failure := errors.New("failure")
+
+		// Exercise
+		consumer.setState(ConsumerStateStarting, nil)
+		consumer.setState(ConsumerStateStarting, nil)
+		consumer.setState(ConsumerStateFailed, failure)
+
+		// Assert
+		if change := <-ch; change.From != ConsumerStateIdle || change.To != ConsumerStateStarting {
+			t.Fatalf("unexpected change %+v", change)
+		}
+		if change := <-ch; change.From != ConsumerStateStarting || change.To != ConsumerStateFailed || change.Err != failure {
+			t.Fatalf("unexpected change %+v", change)
+		}
+		if len(ch) != 0 {
+			t.Fatal("expected no change when the state is the same")
+		}
+		if consumer.State() != ConsumerStateFailed || consumer.Err() != failure {
+			t.Fatalf("unexpected state %s with error %v", consumer.State(), consumer.Err())
+		}
+	})
+
+	t.Run("When a listener is full the transition is dropped without blocking", func(t *testing.T) {
+		// Setup
+		consumer := Consumer{&consumerCore{queueName: "queue"}}
+		ch := make(chan StateChange, 1)
+		consumer.NotifyStateChange(ch)
+
+		// Exercise
+		consumer.setState(ConsumerStateStarting, nil)
+		consumer.setState(ConsumerStateConsuming, nil)
+
+		// Assert
+		if change := <-ch; change.To != ConsumerStateStarting {
+			t.Fatalf("unexpected change %+v", change)
+		}
+		if len(ch) != 0 {
+			t.Fatal("expected the transition to be dropped")
+		}
+		if consumer.State() != ConsumerStateConsuming {
+			t.Fatalf("unexpected state %s", consumer.State())
+		}
+	})
+
+	t.Run("When consumers share a queue each one keeps its state metric", func(t *testing.T) {
+		// Setup
+		first := Consumer{&consumerCore{queueName: "shared-queue", options: consumerOption{name: "first"}}}
+		second := Consumer{&consumerCore{queueName: "shared-queue", options: consumerOption{name: "second"}}}
+
+		// Exercise
+		first.setState(ConsumerStateConsuming, nil)
+		second.setState(ConsumerStateConsuming, nil)
+		second.setState(ConsumerStateStopped, nil)
+
+		// Assert
+		states := consumerStateGauge.MustCurryWith(prometheus.Labels{queue: "shared-queue"})
+		if v := testutil.ToFloat64(states.WithLabelValues("first", string(ConsumerStateConsuming))); v != 1 {
+			t.Fatalf("expected first consumer to be consuming, got %f", v)
+		}
+		if v := testutil.ToFloat64(states.WithLabelValues("second", string(ConsumerStateStopped))); v != 1 {
+			t.Fatalf("expected second consumer to be stopped, got %f", v)
+		}
+		if n := consumerStateGauge.DeletePartialMatch(prometheus.Labels{queue: "shared-queue"}); n != 2 {
+			t.Fatalf("expected one state per consumer, got %d", n)
+		}
+	})
+}
@@ -0,0 +1,76 @@
+package bunnify
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+const (
+	errorMessageHeader      = "x-bunnify-error-message"
+	errorTypeHeader         = "x-bunnify-error-type"
+	errorClassHeader        = "x-bunnify-error-class"
+	handlerRoutingKeyHeader = "x-bunnify-handler-routing-key"
+	attemptsHeader          = "x-bunnify-attempts"
+	failedAtHeader          = "x-bunnify-failed-at"
+)
+
+// deadLetterExchange returns the name of the exchange declared for the dead letter queue.
+func (c *Consumer) deadLetterExchange() string {
+	return fmt.Sprintf("%s-exchange", c.options.deadLetterQueue)
+}
+
+// deadLetter sends the event to the dead letter queue. When failure details are enabled,
+// the event is published to the dead letter exchange with the details of the error and the
+// original is acknowledged once the server confirms the copy. Otherwise, or if the copy is not
+// confirmed, the event is NACKed without requeue, so the server dead letters it through the queue arguments.
+func (c *Consumer) deadLetter(channel *consumerChannel, delivery amqp.Delivery, deliveryInfo DeliveryInfo, err error) {
+	if !c.options.failureDetails || c.options.deadLetterQueue == "" {
+		_ = delivery.Nack(false, false)
+		return
+	}
+
+	headers := retryHeaders(delivery, deliveryInfo, retryCount(delivery.Headers))
+	for k, v := range failureHeaders(deliveryInfo, attempts(delivery.Headers)+1, err) {
+		headers[k] = v
+	}
+
+	if err := channel.republish(c.deadLetterExchange(), "", delivery, headers, ""); err != nil {
+		// The server dead letters the event anyway, only without the failure details
+		notifyEventRepublishFailed(c.options.notificationCh, deliveryInfo.RoutingKey, err)
+		_ = delivery.Nack(false, false)
+		return
+	}
+
+	_ = delivery.Ack(false)
+}
+
+// failureHeaders returns the headers that describe why the event was dead lettered.
+func failureHeaders(deliveryInfo DeliveryInfo, attempts int, err error) amqp.Table {
+	headers := amqp.Table{
+		handlerRoutingKeyHeader: deliveryInfo.RoutingKey,
+		attemptsHeader:          int64(attempts),
+		failedAtHeader:          time.Now().UTC(),
+	}
+
+	if err != nil {
+		headers[errorMessageHeader] = err.Error()
+		headers[errorTypeHeader] = fmt.Sprintf("%T", rootCause(err))
+		headers[errorClassHeader] = classifyError(err)
+	}
+
+	return headers
+}
+
+// rootCause returns the innermost error of the chain.
+func rootCause(err error) error {
+	for {
+		unwrapped := errors.Unwrap(err)
+		if unwrapped == nil {
+			return err
+		}
+		err = unwrapped
+	}
+}
@@ -0,0 +1,35 @@
+package bunnify
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestFailureHeaders(t *testing.T) {
+	// Setup
+	err := fmt.Errorf("handling order: %w", Permanent(errors.New("invalid payload")))
+
+	// Exercise
+	headers := failureHeaders(DeliveryInfo{RoutingKey: "order.created"}, 3, err)
+
+	// Assert
+	if headers[errorMessageHeader] != "handling order: invalid payload" {
+		t.Fatalf("unexpected error message %s", headers[errorMessageHeader])
+	}
+	if headers[errorTypeHeader] != "*errors.errorString" {
+		t.Fatalf("unexpected error type %s", headers[errorTypeHeader])
+	}
+	if headers[errorClassHeader] != errorClassPermanent {
+		t.Fatalf("unexpected error class %s", headers[errorClassHeader])
+	}
+	if headers[handlerRoutingKeyHeader] != "order.created" {
+		t.Fatalf("unexpected routing key %s", headers[handlerRoutingKeyHeader])
+	}
+	if headers[attemptsHeader] != int64(3) {
+		t.Fatalf("unexpected attempts %v", headers[attemptsHeader])
+	}
+	if _, ok := headers[failedAtHeader]; !ok {
+		t.Fatal("expected failure time")
+	}
+}
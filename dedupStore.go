@@ -0,0 +1,93 @@
+package bunnify
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// DedupStore is used to keep track of the events that were handled successfully by a consumer.
+// Implementations must be safe for concurrent use.
+type DedupStore interface {
+	// Seen returns true if the event was already handled successfully on the queue.
+	Seen(queueName, eventID string) (bool, error)
+	// MarkProcessed records that the event was handled successfully on the queue.
+	MarkProcessed(queueName, eventID string) error
+}
+
+// MemoryDedupStore keeps the handled events in memory. Entries expire after the TTL
+// and the least recently used ones are evicted when the capacity is reached.
+type MemoryDedupStore struct {
+	mu       sync.Mutex
+	ttl      time.Duration
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List
+	now      func() time.Time
+}
+
+type dedupEntry struct {
+	key       string
+	expiresAt time.Time
+}
+
+// NewMemoryDedupStore creates an in-memory deduplication store. A TTL or capacity
+// lower or equal than zero means that entries never expire or are never evicted.
+func NewMemoryDedupStore(ttl time.Duration, capacity int) *MemoryDedupStore {
+	return &MemoryDedupStore{
+		ttl:      ttl,
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+		now:      time.Now,
+	}
+}
+
+func (s *MemoryDedupStore) Seen(queueName, eventID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := dedupKey(queueName, eventID)
+	element, ok := s.entries[key]
+	if !ok {
+		return false, nil
+	}
+
+	entry := element.Value.(*dedupEntry)
+	if s.ttl > 0 && s.now().After(entry.expiresAt) {
+		s.order.Remove(element)
+		delete(s.entries, key)
+		return false, nil
+	}
+
+	s.order.MoveToFront(element)
+	return true, nil
+}
+
+func (s *MemoryDedupStore) MarkProcessed(queueName, eventID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := dedupKey(queueName, eventID)
+	expiresAt := s.now().Add(s.ttl)
+
+	if element, ok := s.entries[key]; ok {
+		element.Value.(*dedupEntry).expiresAt = expiresAt
+		s.order.MoveToFront(element)
+		return nil
+	}
+
+	s.entries[key] = s.order.PushFront(&dedupEntry{key: key, expiresAt: expiresAt})
+
+	if s.capacity > 0 && s.order.Len() > s.capacity {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.entries, oldest.Value.(*dedupEntry).key)
+	}
+
+	return nil
+}
+
+func dedupKey(queueName, eventID string) string {
+	return queueName + "/" + eventID
+}
@@ -0,0 +1,64 @@
+package bunnify
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemoryDedupStore(t *testing.T) {
+	t.Run("When event was processed it is seen", func(t *testing.T) {
+		// Setup
+		store := NewMemoryDedupStore(time.Minute, 10)
+
+		// Exercise
+		if err := store.MarkProcessed("queue", "id"); err != nil {
+			t.Fatal(err)
+		}
+
+		// Assert
+		if seen, _ := store.Seen("queue", "id"); !seen {
+			t.Fatal("expected event to be seen")
+		}
+		if seen, _ := store.Seen("other-queue", "id"); seen {
+			t.Fatal("expected event not to be seen on other queue")
+		}
+	})
+
+	t.Run("When entry expired it is not seen", func(t *testing.T) {
+		// Setup
+		now := time.Now()
+		store := NewMemoryDedupStore(time.Minute, 10)
+		store.now = func() time.Time { return now }
+
+		// Exercise
+		_ = store.MarkProcessed("queue", "id")
+		now = now.Add(2 * time.Minute)
+
+		// Assert
+		if seen, _ := store.Seen("queue", "id"); seen {
+			t.Fatal("expected event not to be seen after expiration")
+		}
+	})
+
+	t.Run("When capacity is reached least recently used is evicted", func(t *testing.T) {
+		// Setup
+		store := NewMemoryDedupStore(0, 2)
+
+		// Exercise
+		_ = store.MarkProcessed("queue", "first")
+		_ = store.MarkProcessed("queue", "second")
+		_, _ = store.Seen("queue", "first")
+		_ = store.MarkProcessed("queue", "third")
+
+		// Assert
+		if seen, _ := store.Seen("queue", "second"); seen {
+			t.Fatal("expected second event to be evicted")
+		}
+		if seen, _ := store.Seen("queue", "first"); !seen {
+			t.Fatal("expected first event to be seen")
+		}
+		if seen, _ := store.Seen("queue", "third"); !seen {
+			t.Fatal("expected third event to be seen")
+		}
+	})
+}
@@ -0,0 +1,38 @@
+package bunnify
+
+import (
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// skipDuplicate acknowledges the event and returns true if it was already handled successfully.
+// If the store fails, the event is handled anyway as delivery is at least once.
+func (c *Consumer) skipDuplicate(delivery amqp.Delivery, deliveryInfo DeliveryInfo, eventID string) bool {
+	if c.options.dedupStore == nil || eventID == "" {
+		return false
+	}
+
+	seen, err := c.options.dedupStore.Seen(c.queueName, eventID)
+	if err != nil {
+		notifyDedupStoreFailed(c.options.notificationCh, eventID, err)
+		return false
+	}
+	if !seen {
+		return false
+	}
+
+	_ = delivery.Ack(false)
+	notifyEventDuplicated(c.options.notificationCh, deliveryInfo.RoutingKey, eventID)
+	eventDuplicated(c.queueName, deliveryInfo.RoutingKey)
+	return true
+}
+
+// markProcessed records that the event was handled successfully.
+func (c *Consumer) markProcessed(eventID string) {
+	if c.options.dedupStore == nil || eventID == "" {
+		return
+	}
+
+	if err := c.options.dedupStore.MarkProcessed(c.queueName, eventID); err != nil {
+		notifyDedupStoreFailed(c.options.notificationCh, eventID, err)
+	}
+}
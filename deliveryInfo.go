@@ -1,10 +1,73 @@
 package bunnify
 
 import (
+	"time"
+
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
 func getDeliveryInfo(queueName string, delivery amqp.Delivery) DeliveryInfo {
+	deliveryInfo := getRoutedDeliveryInfo(queueName, delivery)
+	deliveryInfo = withOriginalRoute(deliveryInfo, delivery.Headers)
+
+	deliveryInfo.Redelivered = delivery.Redelivered
+	deliveryInfo.DeliveryCount = deliveryCount(delivery.Headers)
+	deliveryInfo.RetryCount = attempts(delivery.Headers)
+	deliveryInfo.Deaths = getDeaths(delivery.Headers)
+	deliveryInfo.Headers = delivery.Headers
+	deliveryInfo.Properties = Properties{
+		ContentType:     delivery.ContentType,
+		ContentEncoding: delivery.ContentEncoding,
+		DeliveryMode:    delivery.DeliveryMode,
+		Priority:        delivery.Priority,
+		Expiration:      delivery.Expiration,
+		AppID:           delivery.AppId,
+		ReplyTo:         delivery.ReplyTo,
+		Type:            delivery.Type,
+		UserID:          delivery.UserId,
+		MessageID:       delivery.MessageId,
+		CorrelationID:   delivery.CorrelationId,
+		Timestamp:       delivery.Timestamp,
+	}
+
+	return deliveryInfo
+}
+
+// getDeaths returns the whole dead letter history from the x-death header.
+func getDeaths(headers amqp.Table) []Death {
+	entries, ok := headers["x-death"].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	deaths := make([]Death, 0, len(entries))
+	for _, entry := range entries {
+		table, ok := entry.(amqp.Table)
+		if !ok {
+			continue
+		}
+
+		death := Death{}
+		death.Queue, _ = table["queue"].(string)
+		death.Exchange, _ = table["exchange"].(string)
+		death.Reason, _ = table["reason"].(string)
+		death.Count, _ = intValue(table["count"])
+		death.Time, _ = table["time"].(time.Time)
+
+		routingKeys, _ := table["routing-keys"].([]interface{})
+		for _, rk := range routingKeys {
+			if key, ok := rk.(string); ok {
+				death.RoutingKeys = append(death.RoutingKeys, key)
+			}
+		}
+
+		deaths = append(deaths, death)
+	}
+
+	return deaths
+}
+
+func getRoutedDeliveryInfo(queueName string, delivery amqp.Delivery) DeliveryInfo {
 	deliveryInfo := DeliveryInfo{
 		Queue:      queueName,
 		Exchange:   delivery.Exchange,
@@ -49,3 +112,18 @@ func getDeliveryInfo(queueName string, delivery amqp.Delivery) DeliveryInfo {
 
 	return deliveryInfo
 }
+
+// withOriginalRoute overrides the queue, exchange and routing key with the ones stored
+// in the headers when bunnify republished the event for a retry or to dead letter.
+func withOriginalRoute(deliveryInfo DeliveryInfo, headers amqp.Table) DeliveryInfo {
+	if queue, ok := headers[originalQueueHeader].(string); ok {
+		deliveryInfo.Queue = queue
+	}
+	if exchange, ok := headers[originalExchangeHeader].(string); ok {
+		deliveryInfo.Exchange = exchange
+	}
+	if routingKey, ok := headers[originalRoutingKeyHeader].(string); ok {
+		deliveryInfo.RoutingKey = routingKey
+	}
+	return deliveryInfo
+}
@@ -2,6 +2,7 @@ package bunnify
 
 import (
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/rabbitmq/amqp091-go"
@@ -66,4 +67,97 @@ func TestGetDeliveryInfo(t *testing.T) {
 			t.Fatalf("expected routing key %s, got %s", routingKey, info.RoutingKey)
 		}
 	})
+
+	t.Run("When event has been republished for a retry", func(t *testing.T) {
+		// Setup
+		queueName := uuid.NewString()
+		exchange := uuid.NewString()
+		routingKey := uuid.NewString()
+
+		// Exercise
+		info := getDeliveryInfo(queueName, amqp091.Delivery{
+			Headers: map[string]interface{}{
+				originalExchangeHeader:   exchange,
+				originalRoutingKeyHeader: routingKey,
+			},
+			Exchange:   "",
+			RoutingKey: queueName,
+		})
+
+		// Assert
+		if queueName != info.Queue {
+			t.Fatalf("expected queue %s, got %s", queueName, info.Queue)
+		}
+		if exchange != info.Exchange {
+			t.Fatalf("expected exchange %s, got %s", exchange, info.Exchange)
+		}
+		if routingKey != info.RoutingKey {
+			t.Fatalf("expected routing key %s, got %s", routingKey, info.RoutingKey)
+		}
+	})
+
+	t.Run("When event has delivery details", func(t *testing.T) {
+		// Setup
+		deadAt := time.Now().Truncate(time.Second)
+
+		// Exercise
+		info := getDeliveryInfo("queue", amqp091.Delivery{
+			Headers: map[string]interface{}{
+				"x-acquired-count": int64(2),
+				retryCountHeader:   int64(1),
+				"x-death": []interface{}{
+					amqp091.Table{
+						"queue":        "retry-queue",
+						"exchange":     "",
+						"reason":       "expired",
+						"count":        int64(1),
+						"time":         deadAt,
+						"routing-keys": []interface{}{"queue"},
+					},
+					amqp091.Table{
+						"queue":        "queue",
+						"exchange":     "exchange",
+						"reason":       "rejected",
+						"count":        int64(3),
+						"time":         deadAt,
+						"routing-keys": []interface{}{"routing-key"},
+					},
+				},
+			},
+			Redelivered: true,
+			ContentType: "application/json",
+			Priority:    5,
+			AppId:       "app",
+			ReplyTo:     "reply-queue",
+			Exchange:    "exchange",
+			RoutingKey:  "routing-key",
+		})
+
+		// Assert
+		if !info.Redelivered {
+			t.Fatal("expected redelivered")
+		}
+		if info.DeliveryCount != 2 {
+			t.Fatalf("expected delivery count 2, got %d", info.DeliveryCount)
+		}
+		if info.RetryCount != 3 {
+			t.Fatalf("expected retry count 3, got %d", info.RetryCount)
+		}
+		if len(info.Deaths) != 2 {
+			t.Fatalf("expected 2 deaths, got %d", len(info.Deaths))
+		}
+		if info.Deaths[1].Reason != "rejected" || info.Deaths[1].Count != 3 || info.Deaths[1].RoutingKeys[0] != "routing-key" {
+			t.Fatalf("unexpected death %+v", info.Deaths[1])
+		}
+		if !info.Deaths[0].Time.Equal(deadAt) {
+			t.Fatalf("expected death time %s, got %s", deadAt, info.Deaths[0].Time)
+		}
+		if info.Properties.ContentType != "application/json" || info.Properties.Priority != 5 ||
+			info.Properties.AppID != "app" || info.Properties.ReplyTo != "reply-queue" {
+			t.Fatalf("unexpected properties %+v", info.Properties)
+		}
+		if info.Headers["x-acquired-count"] != int64(2) {
+			t.Fatal("expected raw headers")
+		}
+	})
 }
@@ -0,0 +1,50 @@
+package bunnify
+
+import (
+	"fmt"
+	"time"
+)
+
+// Disposition indicates what happens with an event when the handler
+// does not want it to be acknowledged nor treated as a failure.
+type Disposition string
+
+const (
+	// DispositionRequeue puts the event back on the queue without counting it as a retry.
+	DispositionRequeue Disposition = "REQUEUE"
+	// DispositionReject sends the event to dead letter without retrying it.
+	DispositionReject Disposition = "REJECT"
+	// DispositionDefer puts the event back on the queue after a delay without counting it as a retry.
+	DispositionDefer Disposition = "DEFER"
+)
+
+// DispositionError is returned by a handler to choose the disposition of the event.
+// It is not considered a handler failure.
+type DispositionError struct {
+	Disposition Disposition
+	After       time.Duration
+}
+
+func (e *DispositionError) Error() string {
+	if e.Disposition == DispositionDefer {
+		return fmt.Sprintf("event disposition %s after %s", e.Disposition, e.After)
+	}
+	return fmt.Sprintf("event disposition %s", e.Disposition)
+}
+
+// Requeue is returned by a handler to put the event at the back of the queue
+// without counting it as a retry.
+func Requeue() error {
+	return &DispositionError{Disposition: DispositionRequeue}
+}
+
+// Reject is returned by a handler to send the event to dead letter without retrying it.
+func Reject() error {
+	return &DispositionError{Disposition: DispositionReject}
+}
+
+// Defer is returned by a handler to put the event back on the queue once the
+// given duration passes, rounded up to whole milliseconds, without counting it as a retry.
+func Defer(after time.Duration) error {
+	return &DispositionError{Disposition: DispositionDefer, After: after}
+}
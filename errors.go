@@ -1,5 +1,77 @@
 package bunnify
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 var errConnectionClosedByUser = errors.New("connection is already closed by system")
+
+var errConsumerPaused = errors.New("consumer is paused")
+
+// PermanentError wraps a handler error that will not succeed if the event is retried.
+// Events failing with this error skip the retries and go straight to dead letter.
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+// Permanent marks the error returned by a handler as permanent.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &PermanentError{Err: err}
+}
+
+// RetryableError wraps a handler error that might succeed if the event is retried.
+// If After is greater than zero, the event is retried once that duration passes.
+type RetryableError struct {
+	Err   error
+	After time.Duration
+}
+
+func (e *RetryableError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RetryableError) Unwrap() error {
+	return e.Err
+}
+
+// Retryable marks the error returned by a handler as retryable after the given duration.
+// The amount of retries is still bounded by the WithRetries or WithRetryDelays options.
+func Retryable(err error, after time.Duration) error {
+	if err == nil {
+		return nil
+	}
+	return &RetryableError{Err: err, After: after}
+}
+
+const (
+	errorClassPermanent    = "permanent"
+	errorClassRetryable    = "retryable"
+	errorClassUnclassified = "unclassified"
+)
+
+// classifyError returns the class used on metrics for the given handler error.
+func classifyError(err error) string {
+	var permanent *PermanentError
+	if errors.As(err, &permanent) {
+		return errorClassPermanent
+	}
+
+	var retryable *RetryableError
+	if errors.As(err, &retryable) {
+		return errorClassRetryable
+	}
+
+	return errorClassUnclassified
+}
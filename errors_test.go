@@ -0,0 +1,61 @@
+package bunnify
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestClassifyError(t *testing.T) {
+	t.Run("When error is permanent", func(t *testing.T) {
+		// Setup
+		err := fmt.Errorf("wrapped: %w", Permanent(errors.New("invalid payload")))
+
+		// Exercise
+		class := classifyError(err)
+
+		// Assert
+		if class != errorClassPermanent {
+			t.Fatalf("expected class %s, got %s", errorClassPermanent, class)
+		}
+	})
+
+	t.Run("When error is retryable", func(t *testing.T) {
+		// Setup
+		err := Retryable(errors.New("timeout"), time.Second)
+
+		// Exercise
+		class := classifyError(err)
+
+		// Assert
+		if class != errorClassRetryable {
+			t.Fatalf("expected class %s, got %s", errorClassRetryable, class)
+		}
+
+		var retryable *RetryableError
+		if !errors.As(err, &retryable) || retryable.After != time.Second {
+			t.Fatalf("expected retryable error after %s", time.Second)
+		}
+	})
+
+	t.Run("When error is not classified", func(t *testing.T) {
+		// Exercise
+		class := classifyError(errors.New("unknown"))
+
+		// Assert
+		if class != errorClassUnclassified {
+			t.Fatalf("expected class %s, got %s", errorClassUnclassified, class)
+		}
+	})
+
+	t.Run("When error is nil", func(t *testing.T) {
+		// Assert
+		if Permanent(nil) != nil {
+			t.Fatal("expected nil permanent error")
+		}
+		if Retryable(nil, time.Second) != nil {
+			t.Fatal("expected nil retryable error")
+		}
+	})
+}
@@ -3,6 +3,10 @@ package bunnify
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
 )
 
 // EventHandler is the type definition for a function that is used to handle events of a specific type.
@@ -25,3 +29,78 @@ func newWrappedHandler[T any](handler EventHandler[T]) wrappedHandler {
 		return handler(ctx, consumableEvent)
 	}
 }
+
+// BatchEventHandler is the type definition for a function that is used to handle a batch of events of a specific type.
+// To report that only some of the events failed, return a *BatchError with the failures by index.
+type BatchEventHandler[T any] func(ctx context.Context, events []ConsumableEvent[T]) error
+
+// BatchError is returned by a BatchEventHandler when only some of the events of the batch failed.
+// The events without an error on Errors are acknowledged, the rest are NACKed following the retry policy.
+type BatchError struct {
+	Errors map[int]error
+}
+
+func (e *BatchError) Error() string {
+	indexes := make([]int, 0, len(e.Errors))
+	for i := range e.Errors {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+
+	msgs := make([]string, 0, len(indexes))
+	for _, i := range indexes {
+		msgs = append(msgs, fmt.Sprintf("event %d: %s", i, e.Errors[i]))
+	}
+	return fmt.Sprintf("batch failed for %d events: %s", len(indexes), strings.Join(msgs, "; "))
+}
+
+// wrappedBatchHandler is internally used to wrap the generic BatchEventHandler
+// this is to facilitate adding all the different type of T on the same map
+type wrappedBatchHandler func(ctx context.Context, events []unmarshalEvent) error
+
+func newWrappedBatchHandler[T any](handler BatchEventHandler[T]) wrappedBatchHandler {
+	return func(ctx context.Context, events []unmarshalEvent) error {
+		consumableEvents := make([]ConsumableEvent[T], 0, len(events))
+		indexes := make([]int, 0, len(events))
+		failed := make(map[int]error)
+
+		for i, event := range events {
+			consumableEvent := ConsumableEvent[T]{
+				Metadata:     event.Metadata,
+				DeliveryInfo: event.DeliveryInfo,
+			}
+			if err := json.Unmarshal(event.Payload, &consumableEvent.Payload); err != nil {
+				failed[i] = err
+				continue
+			}
+			consumableEvents = append(consumableEvents, consumableEvent)
+			indexes = append(indexes, i)
+		}
+
+		if len(consumableEvents) == 0 {
+			return &BatchError{Errors: failed}
+		}
+
+		err := handler(ctx, consumableEvents)
+		if len(failed) == 0 {
+			return err
+		}
+
+		// Map the failures reported by the handler back to the original indexes
+		var batchErr *BatchError
+		switch {
+		case errors.As(err, &batchErr):
+			for i, e := range batchErr.Errors {
+				if i >= 0 && i < len(indexes) {
+					failed[indexes[i]] = e
+				}
+			}
+		case err != nil:
+			for _, i := range indexes {
+				failed[i] = err
+			}
+		}
+
+		return &BatchError{Errors: failed}
+	}
+}
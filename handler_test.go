@@ -0,0 +1,83 @@
+package bunnify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestWrappedBatchHandler(t *testing.T) {
+	type orderCreated struct {
+		ID string `json:"id"`
+	}
+
+	events := []unmarshalEvent{
+		{Payload: json.RawMessage(`{"id":"1"}`)},
+		{Payload: json.RawMessage(`"not an order"`)},
+		{Payload: json.RawMessage(`{"id":"3"}`)},
+	}
+
+	t.Run("When handler succeeds only unparsable events fail", func(t *testing.T) {
+		// Setup
+		var received []orderCreated
+		handler := newWrappedBatchHandler(func(ctx context.Context, events []ConsumableEvent[orderCreated]) error {
+			for _, e := range events {
+				received = append(received, e.Payload)
+			}
+			return nil
+		})
+
+		// Exercise
+		err := handler(context.TODO(), events)
+
+		// Assert
+		var batchErr *BatchError
+		if !errors.As(err, &batchErr) {
+			t.Fatalf("expected batch error, got %v", err)
+		}
+		if len(batchErr.Errors) != 1 || batchErr.Errors[1] == nil {
+			t.Fatalf("expected only event 1 to fail, got %v", batchErr.Errors)
+		}
+		if len(received) != 2 || received[0].ID != "1" || received[1].ID != "3" {
+			t.Fatalf("expected orders 1 and 3, got %v", received)
+		}
+	})
+
+	t.Run("When handler reports partial failure indexes are mapped back", func(t *testing.T) {
+		// Setup
+		handler := newWrappedBatchHandler(func(ctx context.Context, events []ConsumableEvent[orderCreated]) error {
+			return &BatchError{Errors: map[int]error{1: errors.New("order 3 failed")}}
+		})
+
+		// Exercise
+		err := handler(context.TODO(), events)
+
+		// Assert
+		var batchErr *BatchError
+		if !errors.As(err, &batchErr) {
+			t.Fatalf("expected batch error, got %v", err)
+		}
+		if batchErr.Errors[0] != nil {
+			t.Fatalf("expected event 0 to succeed, got %s", batchErr.Errors[0])
+		}
+		if batchErr.Errors[1] == nil || batchErr.Errors[2] == nil {
+			t.Fatalf("expected events 1 and 2 to fail, got %v", batchErr.Errors)
+		}
+	})
+
+	t.Run("When handler fails every event fails", func(t *testing.T) {
+		// Setup
+		handler := newWrappedBatchHandler(func(ctx context.Context, events []ConsumableEvent[orderCreated]) error {
+			return errors.New("warehouse unavailable")
+		})
+
+		// Exercise
+		err := handler(context.TODO(), events[:1])
+
+		// Assert
+		if err == nil || err.Error() != "warehouse unavailable" {
+			t.Fatalf("expected handler error, got %v", err)
+		}
+	})
+}
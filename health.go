@@ -0,0 +1,230 @@
+package bunnify
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// ChannelState is the state of the channel used by a publisher.
+type ChannelState string
+
+const (
+	// ChannelStateIdle is the state before the first publish.
+	ChannelStateIdle ChannelState = "idle"
+	// ChannelStateOpen is the state while the channel can be used for publishing.
+	ChannelStateOpen ChannelState = "open"
+	// ChannelStateClosed is the state after the channel was closed, until the next publish.
+	ChannelStateClosed ChannelState = "closed"
+)
+
+type healthCheckerOption struct {
+	consumers  []*Consumer
+	publishers []*Publisher
+}
+
+// WithHealthConsumers specifies the consumers which state is part of the health report.
+func WithHealthConsumers(consumers ...*Consumer) func(*healthCheckerOption) {
+	return func(opt *healthCheckerOption) {
+		opt.consumers = append(opt.consumers, consumers...)
+	}
+}
+
+// WithHealthPublishers specifies the publishers which channel state is part of the health report.
+func WithHealthPublishers(publishers ...*Publisher) func(*healthCheckerOption) {
+	return func(opt *healthCheckerOption) {
+		opt.publishers = append(opt.publishers, publishers...)
+	}
+}
+
+// HealthReport aggregates the state of the connection, consumers and publishers.
+// Live is false when a consumer failed and cannot recover. Ready is true when the
+// connection is established and every consumer is either consuming or paused.
+// Publishers do not affect readiness, as a closed channel is reopened on the next publish.
+type HealthReport struct {
+	Live       bool              `json:"live"`
+	Ready      bool              `json:"ready"`
+	Connected  bool              `json:"connected"`
+	Consumers  []ConsumerHealth  `json:"consumers"`
+	Publishers []PublisherHealth `json:"publishers"`
+}
+
+// ConsumerHealth describes the state of a consumer.
+// Error is the cause of the failed state and LastHandlerError the last error returned by a handler.
+type ConsumerHealth struct {
+	Queue              string        `json:"queue"`
+	State              ConsumerState `json:"state"`
+	Handlers           []string      `json:"handlers"`
+	InFlight           int64         `json:"inFlight"`
+	Error              string        `json:"error,omitempty"`
+	LastHandlerError   string        `json:"lastHandlerError,omitempty"`
+	LastHandlerErrorAt time.Time     `json:"lastHandlerErrorAt,omitzero"`
+}
+
+// PublisherHealth describes the state of a publisher channel.
+type PublisherHealth struct {
+	Channel ChannelState `json:"channel"`
+}
+
+// HealthChecker reports the health of the connection and the consumers and publishers supplied.
+type HealthChecker struct {
+	isConnected func() bool
+	options     healthCheckerOption
+}
+
+// NewHealthChecker creates a health checker for the connection and
+// the consumers and publishers specified with the options.
+func (c *Connection) NewHealthChecker(opts ...func(*healthCheckerOption)) *HealthChecker {
+	options := healthCheckerOption{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return &HealthChecker{
+		isConnected: c.IsConnected,
+		options:     options,
+	}
+}
+
+// Check returns the current health report.
+func (h *HealthChecker) Check() HealthReport {
+	report := HealthReport{
+		Live:       true,
+		Connected:  h.isConnected(),
+		Consumers:  make([]ConsumerHealth, 0, len(h.options.consumers)),
+		Publishers: make([]PublisherHealth, 0, len(h.options.publishers)),
+	}
+	report.Ready = report.Connected
+
+	for _, consumer := range h.options.consumers {
+		health := consumer.health()
+		switch health.State {
+		case ConsumerStateConsuming, ConsumerStatePaused:
+		case ConsumerStateFailed:
+			report.Live = false
+			report.Ready = false
+		default:
+			report.Ready = false
+		}
+		report.Consumers = append(report.Consumers, health)
+	}
+
+	for _, publisher := range h.options.publishers {
+		report.Publishers = append(report.Publishers, PublisherHealth{Channel: publisher.ChannelState()})
+	}
+
+	return report
+}
+
+// Handler returns an http.Handler serving the health report. The paths are relative,
+// so it can be mounted under a prefix with http.StripPrefix:
+//   - /live responds with the report as JSON, with status 503 when not live.
+//   - /ready responds with the report as JSON, with status 503 when not ready.
+//   - /debug responds with a page listing the consumers, queues, handlers, in-flight events and last errors.
+func (h *HealthChecker) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /live", func(w http.ResponseWriter, _ *http.Request) {
+		report := h.Check()
+		writeHealthReport(w, report, report.Live)
+	})
+	mux.HandleFunc("GET /ready", func(w http.ResponseWriter, _ *http.Request) {
+		report := h.Check()
+		writeHealthReport(w, report, report.Ready)
+	})
+	mux.HandleFunc("GET /debug", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_ = debugPage.Execute(w, h.Check())
+	})
+	return mux
+}
+
+func writeHealthReport(w http.ResponseWriter, report HealthReport, healthy bool) {
+	w.Header().Set("Content-Type", "application/json")
+	if !healthy {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_ = json.NewEncoder(w).Encode(report)
+}
+
+var debugPage = template.Must(template.New("debug").Parse(`<!DOCTYPE html>
+<html>
+<head><title>bunnify</title></head>
+<body>
+<h1>bunnify</h1>
+<p>Connected: {{.Connected}} | Live: {{.Live}} | Ready: {{.Ready}}</p>
+<h2>Consumers</h2>
+<table border="1">
+<tr><th>Queue</th><th>State</th><th>Handlers</th><th>In flight</th><th>Error</th><th>Last handler error</th></tr>
+{{range .Consumers}}<tr>
+<td>{{.Queue}}</td>
+<td>{{.State}}</td>
+<td>{{range .Handlers}}{{.}}<br>{{end}}</td>
+<td>{{.InFlight}}</td>
+<td>{{.Error}}</td>
+<td>{{if .LastHandlerError}}{{.LastHandlerErrorAt.Format "2006-01-02T15:04:05Z07:00"}}: {{.LastHandlerError}}{{end}}</td>
+</tr>
+{{end}}</table>
+<h2>Publishers</h2>
+<table border="1">
+<tr><th>#</th><th>Channel</th></tr>
+{{range $i, $p := .Publishers}}<tr><td>{{$i}}</td><td>{{$p.Channel}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+// health returns the state of the consumer for the health report.
+func (c *Consumer) health() ConsumerHealth {
+	health := ConsumerHealth{
+		Queue:    c.queueName,
+		State:    c.State(),
+		Handlers: c.handlerNames(),
+		InFlight: c.inFlight.Load(),
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.lastErr != nil {
+		health.Error = c.lastErr.Error()
+	}
+	if c.lastHandlerErr != nil {
+		health.LastHandlerError = c.lastHandlerErr.Error()
+		health.LastHandlerErrorAt = c.lastHandlerErrAt
+	}
+	return health
+}
+
+// handlerNames describes the handlers of the consumer: the routing keys, sorted,
+// followed by the header handlers and the default handler.
+func (c *Consumer) handlerNames() []string {
+	c.handlersMu.RLock()
+	defer c.handlersMu.RUnlock()
+
+	names := make([]string, 0)
+	for routingKey := range c.options.handlers {
+		names = append(names, routingKey)
+	}
+	for routingKey := range c.options.batchHandlers {
+		names = append(names, fmt.Sprintf("%s (batch)", routingKey))
+	}
+	slices.Sort(names)
+
+	for _, h := range c.options.headerHandlers {
+		names = append(names, fmt.Sprintf("headers %s %v", h.match, h.headers))
+	}
+	if c.options.defaultHandler != nil {
+		names = append(names, "default")
+	}
+	return names
+}
+
+// recordHandlerError keeps the last error returned by a handler for the health report.
+func (c *Consumer) recordHandlerError(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lastHandlerErr = err
+	c.lastHandlerErrAt = time.Now()
+}
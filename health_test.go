@@ -0,0 +1,155 @@
+package bunnify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestHealthChecker(t *testing.T) {
+	newConsumer := func(state ConsumerState) *Consumer {
+		consumer := &Consumer{&consumerCore{
+			queueName: "queue",
+			options: consumerOption{
+				handlers: map[string]wrappedHandler{
+					"b": nil,
+					"a": nil,
+				},
+				batchHandlers: map[string]batchHandler{"c": {}},
+				defaultHandler: func(ctx context.Context, event unmarshalEvent) error {
+					return nil
+				},
+			},
+		}}
+		consumer.setState(state, errors.New("could not reconnect"))
+		return consumer
+	}
+
+	t.Run("When connected and consuming it is ready", func(t *testing.T) {
+		// Setup
+		consumer := newConsumer(ConsumerStateConsuming)
+		consumer.inFlight.Add(2)
+		checker := HealthChecker{
+			isConnected: func() bool { return true },
+			options: healthCheckerOption{
+				consumers:  []*Consumer{consumer},
+				publishers: []*Publisher{{}},
+			},
+		}
+
+		// Exercise
+		report := checker.Check()
+
+		// Assert
+		if !report.Live || !report.Ready || !report.Connected {
+			t.Fatalf("expected live and ready, got %+v", report)
+		}
+		health := report.Consumers[0]
+		if health.Queue != "queue" || health.State != ConsumerStateConsuming || health.InFlight != 2 {
+			t.Fatalf("unexpected consumer health %+v", health)
+		}
+		expectedHandlers := []string{"a", "b", "c (batch)", "default"}
+		if !slices.Equal(health.Handlers, expectedHandlers) {
+			t.Fatalf("expected handlers %v, got %v", expectedHandlers, health.Handlers)
+		}
+		if report.Publishers[0].Channel != ChannelStateIdle {
+			t.Fatalf("expected publisher channel %s, got %s", ChannelStateIdle, report.Publishers[0].Channel)
+		}
+	})
+
+	t.Run("When not connected or reconnecting it is live but not ready", func(t *testing.T) {
+		for _, tc := range []struct {
+			connected bool
+			state     ConsumerState
+		}{
+			{false, ConsumerStateConsuming},
+			{true, ConsumerStateReconnecting},
+			{true, ConsumerStateIdle},
+		} {
+			// Setup
+			checker := HealthChecker{
+				isConnected: func() bool { return tc.connected },
+				options:     healthCheckerOption{consumers: []*Consumer{newConsumer(tc.state)}},
+			}
+
+			// Exercise
+			report := checker.Check()
+
+			// Assert
+			if !report.Live || report.Ready {
+				t.Fatalf("expected live and not ready for %+v, got %+v", tc, report)
+			}
+		}
+	})
+
+	t.Run("When a consumer failed it is not live", func(t *testing.T) {
+		// Setup
+		checker := HealthChecker{
+			isConnected: func() bool { return true },
+			options:     healthCheckerOption{consumers: []*Consumer{newConsumer(ConsumerStateFailed)}},
+		}
+		recorder := httptest.NewRecorder()
+
+		// Exercise
+		checker.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/live", nil))
+
+		// Assert
+		if recorder.Code != http.StatusServiceUnavailable {
+			t.Fatalf("expected status %d, got %d", http.StatusServiceUnavailable, recorder.Code)
+		}
+		var report HealthReport
+		if err := json.NewDecoder(recorder.Body).Decode(&report); err != nil {
+			t.Fatal(err)
+		}
+		if report.Live || report.Consumers[0].Error != "could not reconnect" {
+			t.Fatalf("unexpected report %+v", report)
+		}
+	})
+
+	t.Run("When ready the readiness endpoint responds ok", func(t *testing.T) {
+		// Setup
+		checker := HealthChecker{
+			isConnected: func() bool { return true },
+			options:     healthCheckerOption{consumers: []*Consumer{newConsumer(ConsumerStatePaused)}},
+		}
+		recorder := httptest.NewRecorder()
+
+		// Exercise
+		checker.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/ready", nil))
+
+		// Assert
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d", http.StatusOK, recorder.Code)
+		}
+		if recorder.Header().Get("Content-Type") != "application/json" {
+			t.Fatalf("expected json, got %s", recorder.Header().Get("Content-Type"))
+		}
+	})
+
+	t.Run("Debug page lists the consumers and last errors", func(t *testing.T) {
+		// Setup
+		consumer := newConsumer(ConsumerStateConsuming)
+		consumer.recordHandlerError(errors.New("<handler> failed"))
+		checker := HealthChecker{
+			isConnected: func() bool { return true },
+			options:     healthCheckerOption{consumers: []*Consumer{consumer}},
+		}
+		recorder := httptest.NewRecorder()
+
+		// Exercise
+		checker.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/debug", nil))
+
+		// Assert
+		body := recorder.Body.String()
+		for _, expected := range []string{"queue", "consuming", "c (batch)", "&lt;handler&gt; failed"} {
+			if !strings.Contains(body, expected) {
+				t.Fatalf("expected debug page to contain %q, got %s", expected, body)
+			}
+		}
+	})
+}
@@ -0,0 +1,153 @@
+package bunnify
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestManagementAPIInspect(t *testing.T) {
+	// Setup
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, _ := r.BasicAuth()
+		if user != "guest" || password != "guest" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.EscapedPath() {
+		case "/api/queues/%2F/orders":
+			_, _ = w.Write([]byte(`{"type":"classic","durable":true,"auto_delete":false,"arguments":{"x-max-length":10}}`))
+		case "/api/exchanges/%2F/events":
+			_, _ = w.Write([]byte(`{"type":"topic","durable":true,"auto_delete":false,"internal":false,"arguments":{}}`))
+		case "/api/bindings/%2F/e/events/q/orders":
+			_, _ = w.Write([]byte(`[{"routing_key":"order.created","arguments":{}}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	api := newManagementAPI("http://guest:guest@"+server.Listener.Addr().String(), "amqp://localhost:5672")
+
+	t.Run("When the queue arguments differ each one is reported", func(t *testing.T) {
+		// Exercise
+		mismatches, err := QueueDefinition{
+			Name:      "orders",
+			Durable:   true,
+			Arguments: map[string]any{"x-queue-type": "quorum", "x-max-length": 10},
+		}.inspect(api)
+
+		// Assert
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := []TopologyMismatch{
+			{Entity: "queue orders", Property: "x-queue-type", Expected: `"quorum"`, Actual: `"classic"`},
+		}
+		if !reflect.DeepEqual(mismatches, expected) {
+			t.Fatalf("expected %v, got %v", expected, mismatches)
+		}
+	})
+
+	t.Run("When the exchange differs each property is reported", func(t *testing.T) {
+		// Exercise
+		mismatches, err := ExchangeDefinition{
+			Name:              "events",
+			Kind:              ExchangeKindTopic,
+			AlternateExchange: "unrouted",
+		}.inspect(api)
+
+		// Assert
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := []TopologyMismatch{
+			{Entity: "exchange events", Property: "alternate-exchange", Expected: `"unrouted"`, Actual: notSet},
+			{Entity: "exchange events", Property: "durable", Expected: "false", Actual: "true"},
+		}
+		if !reflect.DeepEqual(mismatches, expected) {
+			t.Fatalf("expected %v, got %v", expected, mismatches)
+		}
+	})
+
+	t.Run("When the queue does not exist it is reported", func(t *testing.T) {
+		// Exercise
+		mismatches, err := QueueDefinition{Name: "missing", Durable: true}.inspect(api)
+
+		// Assert
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := []TopologyMismatch{
+			{Entity: "queue missing", Expected: "durable queue", Actual: "does not exist"},
+		}
+		if !reflect.DeepEqual(mismatches, expected) {
+			t.Fatalf("expected %v, got %v", expected, mismatches)
+		}
+	})
+
+	t.Run("When the binding exists it is not reported", func(t *testing.T) {
+		// Exercise
+		mismatches, err := inspectBinding(api, "binding", "events", "q", "orders", "order.created", nil)
+
+		// Assert
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(mismatches) != 0 {
+			t.Fatalf("expected no mismatches, got %v", mismatches)
+		}
+	})
+
+	t.Run("When the binding does not exist it is reported", func(t *testing.T) {
+		// Exercise
+		mismatches, err := inspectBinding(api, "binding", "events", "q", "orders", "order.deleted", nil)
+
+		// Assert
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := []TopologyMismatch{
+			{Entity: "binding", Expected: "bound with arguments {}", Actual: "not bound"},
+		}
+		if !reflect.DeepEqual(mismatches, expected) {
+			t.Fatalf("expected %v, got %v", expected, mismatches)
+		}
+	})
+
+	t.Run("When the api cannot be read an error is returned", func(t *testing.T) {
+		// Setup
+		unauthorized := newManagementAPI("http://"+server.Listener.Addr().String(), "amqp://localhost:5672")
+
+		// Exercise
+		_, err := QueueDefinition{Name: "orders"}.inspect(unauthorized)
+
+		// Assert
+		if err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
+
+func TestTopologyMismatchErrorWithProperty(t *testing.T) {
+	// Setup
+	err := &TopologyMismatchError{Mismatches: []TopologyMismatch{{
+		Entity:   "queue orders",
+		Property: "x-queue-type",
+		Expected: `"quorum"`,
+		Actual:   `"classic"`,
+	}}}
+
+	// Exercise
+	msg := err.Error()
+
+	// Assert
+	expected := "topology does not match for 1 entities:\n" +
+		"- queue orders x-queue-type: \"quorum\"\n" +
+		"+ queue orders x-queue-type: \"classic\""
+	if msg != expected {
+		t.Fatalf("expected %s, got %s", expected, msg)
+	}
+}
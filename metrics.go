@@ -11,6 +11,9 @@ const (
 	exchange   = "exchange"
 	result     = "result"
 	routingKey = "routing_key"
+	errorClass = "error_class"
+	state      = "state"
+	consumer   = "consumer"
 )
 
 var (
@@ -35,11 +38,18 @@ var (
 		}, []string{queue, routingKey},
 	)
 
+	eventDuplicatedCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "amqp_events_duplicated",
+			Help: "Count of AMQP events skipped as they were already processed",
+		}, []string{queue, routingKey},
+	)
+
 	eventNackCounter = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "amqp_events_nack",
 			Help: "Count of AMQP events that were not acknowledged",
-		}, []string{queue, routingKey},
+		}, []string{queue, routingKey, errorClass},
 	)
 
 	eventAckCounter = prometheus.NewCounterVec(
@@ -70,6 +80,13 @@ var (
 			Help: "Count of AMQP events that could not be published",
 		}, []string{exchange, routingKey},
 	)
+
+	consumerStateGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "amqp_consumer_state",
+			Help: "State of the AMQP consumer, the current state is set to 1",
+		}, []string{queue, consumer, state},
+	)
 )
 
 func eventReceived(queue string, routingKey string) {
@@ -84,8 +101,12 @@ func eventNotParsable(queue string, routingKey string) {
 	eventNotParsableCounter.WithLabelValues(queue, routingKey).Inc()
 }
 
-func eventNack(queue string, routingKey string, milliseconds int64) {
-	eventNackCounter.WithLabelValues(queue, routingKey).Inc()
+func eventDuplicated(queue string, routingKey string) {
+	eventDuplicatedCounter.WithLabelValues(queue, routingKey).Inc()
+}
+
+func eventNack(queue string, routingKey string, errorClass string, milliseconds int64) {
+	eventNackCounter.WithLabelValues(queue, routingKey, errorClass).Inc()
 
 	eventProcessedDuration.
 		WithLabelValues(queue, routingKey, "NACK").
@@ -100,6 +121,12 @@ func eventAck(queue string, routingKey string, milliseconds int64) {
 		Observe(float64(milliseconds))
 }
 
+func eventDisposed(queue string, routingKey string, disposition Disposition, milliseconds int64) {
+	eventProcessedDuration.
+		WithLabelValues(queue, routingKey, string(disposition)).
+		Observe(float64(milliseconds))
+}
+
 func eventPublishSucceed(exchange string, routingKey string) {
 	eventPublishSucceedCounter.WithLabelValues(exchange, routingKey).Inc()
 }
@@ -108,6 +135,13 @@ func eventPublishFailed(exchange string, routingKey string) {
 	eventPublishFailedCounter.WithLabelValues(exchange, routingKey).Inc()
 }
 
+// consumerStateChanged replaces the state of the consumer. The caller serializes the changes
+// of each consumer, so the previous state is not deleted after the new one is set.
+func consumerStateChanged(queueName string, consumerName string, consumerState string) {
+	consumerStateGauge.DeletePartialMatch(prometheus.Labels{queue: queueName, consumer: consumerName})
+	consumerStateGauge.WithLabelValues(queueName, consumerName, consumerState).Set(1)
+}
+
 func InitMetrics(registerer prometheus.Registerer) error {
 	collectors := []prometheus.Collector{
 		eventReceivedCounter,
@@ -115,9 +149,11 @@ func InitMetrics(registerer prometheus.Registerer) error {
 		eventNackCounter,
 		eventWithoutHandlerCounter,
 		eventNotParsableCounter,
+		eventDuplicatedCounter,
 		eventProcessedDuration,
 		eventPublishSucceedCounter,
 		eventPublishFailedCounter,
+		consumerStateGauge,
 	}
 	for _, collector := range collectors {
 		mv, ok := collector.(metricResetter)
@@ -97,11 +97,21 @@ func notifyChannelFailed(ch chan<- Notification, source NotificationSource, err
 	}
 }
 
-func notifyEventHandlerNotFound(ch chan<- Notification, routingKey string) {
+func notifyChannelRestored(ch chan<- Notification, source NotificationSource, attempts int) {
+	if ch != nil {
+		ch <- Notification{
+			Type:    NotificationTypeInfo,
+			Message: fmt.Sprintf("restored connection to channel after %d attempts", attempts),
+			Source:  source,
+		}
+	}
+}
+
+func notifyEventHandlerNotFound(ch chan<- Notification, routingKey string, messageID string, preview string) {
 	if ch != nil {
 		ch <- Notification{
 			Type:    NotificationTypeError,
-			Message: fmt.Sprintf("event handler for %s was not found", routingKey),
+			Message: fmt.Sprintf("event handler for %s was not found, message id %s, body %s", routingKey, messageID, preview),
 			Source:  NotificationSourceConsumer,
 		}
 	}
@@ -126,3 +136,143 @@ func notifyEventHandlerFailed(ch chan<- Notification, routingKey string, took in
 		}
 	}
 }
+
+func notifyEventRepublishFailed(ch chan<- Notification, routingKey string, err error) {
+	if ch != nil {
+		ch <- Notification{
+			Type:    NotificationTypeError,
+			Message: fmt.Sprintf("event for %s could not be republished for retry, error: %s", routingKey, err),
+			Source:  NotificationSourceConsumer,
+		}
+	}
+}
+
+func notifyEventHandlerDisposed(ch chan<- Notification, routingKey string, disposition Disposition, took int64) {
+	if ch != nil {
+		ch <- Notification{
+			Type:    NotificationTypeInfo,
+			Message: fmt.Sprintf("event handler for %s returned disposition %s, took %d milliseconds", routingKey, disposition, took),
+			Source:  NotificationSourceConsumer,
+		}
+	}
+}
+
+func notifyConsumerActive(ch chan<- Notification, queueName string) {
+	if ch != nil {
+		ch <- Notification{
+			Type:    NotificationTypeInfo,
+			Message: fmt.Sprintf("consumer received events as the single active consumer for %s", queueName),
+			Source:  NotificationSourceConsumer,
+		}
+	}
+}
+
+func notifyOffsetSaveFailed(ch chan<- Notification, queueName string, err error) {
+	if ch != nil {
+		ch <- Notification{
+			Type:    NotificationTypeError,
+			Message: fmt.Sprintf("failed to save stream offset for %s, error: %s", queueName, err),
+			Source:  NotificationSourceConsumer,
+		}
+	}
+}
+
+func notifyEventDuplicated(ch chan<- Notification, routingKey string, eventID string) {
+	if ch != nil {
+		ch <- Notification{
+			Type:    NotificationTypeInfo,
+			Message: fmt.Sprintf("event %s for %s was already processed, skipping", eventID, routingKey),
+			Source:  NotificationSourceConsumer,
+		}
+	}
+}
+
+func notifyDedupStoreFailed(ch chan<- Notification, eventID string, err error) {
+	if ch != nil {
+		ch <- Notification{
+			Type:    NotificationTypeError,
+			Message: fmt.Sprintf("deduplication store failed for event %s, error: %s", eventID, err),
+			Source:  NotificationSourceConsumer,
+		}
+	}
+}
+
+func notifyEventRedriveFailed(ch chan<- Notification, routingKey string, err error) {
+	if ch != nil {
+		ch <- Notification{
+			Type:    NotificationTypeError,
+			Message: fmt.Sprintf("event for %s could not be redriven, error: %s", routingKey, err),
+			Source:  NotificationSourceConsumer,
+		}
+	}
+}
+
+func notifyEventNotParsable(ch chan<- Notification, routingKey string, messageID string, preview string, err error) {
+	if ch != nil {
+		ch <- Notification{
+			Type:    NotificationTypeError,
+			Message: fmt.Sprintf("event with routing key %s and message id %s could not be parsed, body %s, error %s", routingKey, messageID, preview, err),
+			Source:  NotificationSourceConsumer,
+		}
+	}
+}
+
+func notifyEventParked(ch chan<- Notification, routingKey string, messageID string, preview string, queueName string, reason string) {
+	if ch != nil {
+		ch <- Notification{
+			Type:    NotificationTypeInfo,
+			Message: fmt.Sprintf("event with routing key %s and message id %s parked in %s, reason %s, body %s", routingKey, messageID, queueName, reason, preview),
+			Source:  NotificationSourceConsumer,
+		}
+	}
+}
+
+func notifyConsumerPaused(ch chan<- Notification, queueName string) {
+	if ch != nil {
+		ch <- Notification{
+			Type:    NotificationTypeInfo,
+			Message: fmt.Sprintf("consumer paused on queue %s", queueName),
+			Source:  NotificationSourceConsumer,
+		}
+	}
+}
+
+func notifyConsumerResumed(ch chan<- Notification, queueName string) {
+	if ch != nil {
+		ch <- Notification{
+			Type:    NotificationTypeInfo,
+			Message: fmt.Sprintf("consumer resumed on queue %s", queueName),
+			Source:  NotificationSourceConsumer,
+		}
+	}
+}
+
+func notifyHandlerBindingFailed(ch chan<- Notification, queueName string, routingKey string, err error) {
+	if ch != nil {
+		ch <- Notification{
+			Type:    NotificationTypeError,
+			Message: fmt.Sprintf("handler for routing key %s added to consumer on queue %s but binding failed, error %s", routingKey, queueName, err),
+			Source:  NotificationSourceConsumer,
+		}
+	}
+}
+
+func notifyTopologyMismatch(ch chan<- Notification, err error) {
+	if ch != nil {
+		ch <- Notification{
+			Type:    NotificationTypeError,
+			Message: fmt.Sprintf("failed to verify topology, error %s", err),
+			Source:  NotificationSourceConnection,
+		}
+	}
+}
+
+func notifyTopologyFailed(ch chan<- Notification, entity string, err error) {
+	if ch != nil {
+		ch <- Notification{
+			Type:    NotificationTypeError,
+			Message: fmt.Sprintf("failed to declare %s, error %s", entity, err),
+			Source:  NotificationSourceConnection,
+		}
+	}
+}
@@ -7,7 +7,7 @@ import (
 
 func TestNotifications(t *testing.T) {
 	// Setup
-	ch := make(chan Notification, 11)
+	ch := make(chan Notification, 25)
 
 	// Exercise
 	notifyConnectionEstablished(ch)
@@ -19,7 +19,22 @@ func TestNotifications(t *testing.T) {
 	notifyChannelFailed(ch, NotificationSourceConnection, fmt.Errorf("error"))
 	notifyEventHandlerSucceed(ch, "routing", 10)
 	notifyEventHandlerFailed(ch, "routing", 20, fmt.Errorf("error"))
-	notifyEventHandlerNotFound(ch, "routing")
+	notifyEventHandlerNotFound(ch, "routing", "id", "body")
+	notifyEventRepublishFailed(ch, "routing", fmt.Errorf("error"))
+	notifyEventHandlerDisposed(ch, "routing", DispositionDefer, 10)
+	notifyConsumerActive(ch, "queue")
+	notifyOffsetSaveFailed(ch, "queue", fmt.Errorf("error"))
+	notifyEventDuplicated(ch, "routing", "id")
+	notifyDedupStoreFailed(ch, "id", fmt.Errorf("error"))
+	notifyEventRedriveFailed(ch, "routing", fmt.Errorf("error"))
+	notifyEventNotParsable(ch, "routing", "id", "body", fmt.Errorf("error"))
+	notifyEventParked(ch, "routing", "id", "body", "queue", "reason")
+	notifyConsumerPaused(ch, "queue")
+	notifyConsumerResumed(ch, "queue")
+	notifyTopologyFailed(ch, "queue", fmt.Errorf("error"))
+	notifyHandlerBindingFailed(ch, "queue", "routing", fmt.Errorf("error"))
+	notifyChannelRestored(ch, NotificationSourceConsumer, 2)
+	notifyTopologyMismatch(ch, fmt.Errorf("error"))
 
 	// Assert
 	if (<-ch).Type != NotificationTypeInfo {
@@ -52,4 +67,49 @@ func TestNotifications(t *testing.T) {
 	if (<-ch).Type != NotificationTypeError {
 		t.Fatal("expected notification type error")
 	}
+	if (<-ch).Type != NotificationTypeError {
+		t.Fatal("expected notification type error")
+	}
+	if (<-ch).Type != NotificationTypeInfo {
+		t.Fatal("expected notification type info")
+	}
+	if (<-ch).Type != NotificationTypeInfo {
+		t.Fatal("expected notification type info")
+	}
+	if (<-ch).Type != NotificationTypeError {
+		t.Fatal("expected notification type error")
+	}
+	if (<-ch).Type != NotificationTypeInfo {
+		t.Fatal("expected notification type info")
+	}
+	if (<-ch).Type != NotificationTypeError {
+		t.Fatal("expected notification type error")
+	}
+	if (<-ch).Type != NotificationTypeError {
+		t.Fatal("expected notification type error")
+	}
+	if (<-ch).Type != NotificationTypeError {
+		t.Fatal("expected notification type error")
+	}
+	if (<-ch).Type != NotificationTypeInfo {
+		t.Fatal("expected notification type info")
+	}
+	if (<-ch).Type != NotificationTypeInfo {
+		t.Fatal("expected notification type info")
+	}
+	if (<-ch).Type != NotificationTypeInfo {
+		t.Fatal("expected notification type info")
+	}
+	if (<-ch).Type != NotificationTypeError {
+		t.Fatal("expected notification type error")
+	}
+	if (<-ch).Type != NotificationTypeError {
+		t.Fatal("expected notification type error")
+	}
+	if (<-ch).Type != NotificationTypeInfo {
+		t.Fatal("expected notification type info")
+	}
+	if (<-ch).Type != NotificationTypeError {
+		t.Fatal("expected notification type error")
+	}
 }
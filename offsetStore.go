@@ -0,0 +1,120 @@
+package bunnify
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// OffsetStore is used to keep track of the offset up to which a stream consumer handled every event.
+// The offsets are stored by the consumer name, set with WithConsumerName, so consumers reading the
+// same stream independently do not overwrite each other. Implementations must be safe for concurrent use.
+type OffsetStore interface {
+	// Load returns the stored offset for the consumer, found is false if there is none.
+	Load(consumerName string) (offset int64, found bool, err error)
+	// Save stores the offset for the consumer.
+	Save(consumerName string, offset int64) error
+}
+
+// MemoryOffsetStore keeps the offsets in memory. It is useful to resume
+// after reconnections, but the offsets are lost when the application stops.
+type MemoryOffsetStore struct {
+	mu      sync.Mutex
+	offsets map[string]int64
+}
+
+// NewMemoryOffsetStore creates an empty in-memory offset store.
+func NewMemoryOffsetStore() *MemoryOffsetStore {
+	return &MemoryOffsetStore{offsets: make(map[string]int64)}
+}
+
+func (s *MemoryOffsetStore) Load(consumerName string) (int64, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	offset, ok := s.offsets[consumerName]
+	return offset, ok, nil
+}
+
+// Save stores the offset if it is greater than the current one, so the events a channel
+// settles after the consumer reconnected do not move it back.
+func (s *MemoryOffsetStore) Save(consumerName string, offset int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if current, ok := s.offsets[consumerName]; !ok || offset > current {
+		s.offsets[consumerName] = offset
+	}
+	return nil
+}
+
+// FileOffsetStore keeps the offsets on a directory, using a file per consumer.
+type FileOffsetStore struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// NewFileOffsetStore creates an offset store on the given directory, creating it if needed.
+func NewFileOffsetStore(dir string) (*FileOffsetStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("could not create offset directory: %w", err)
+	}
+	return &FileOffsetStore{dir: dir}, nil
+}
+
+func (s *FileOffsetStore) Load(consumerName string) (int64, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.load(consumerName)
+}
+
+// Save stores the offset if it is greater than the current one, so the events a channel
+// settles after the consumer reconnected do not move it back. The file is replaced atomically.
+func (s *FileOffsetStore) Save(consumerName string, offset int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, found, err := s.load(consumerName)
+	if err != nil {
+		return err
+	}
+	if found && offset <= current {
+		return nil
+	}
+
+	path := s.path(consumerName)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(strconv.FormatInt(offset, 10)), 0o644); err != nil {
+		return fmt.Errorf("could not write offset: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("could not write offset: %w", err)
+	}
+	return nil
+}
+
+func (s *FileOffsetStore) load(consumerName string) (int64, bool, error) {
+	b, err := os.ReadFile(s.path(consumerName))
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("could not read offset: %w", err)
+	}
+
+	offset, err := strconv.ParseInt(strings.TrimSpace(string(b)), 10, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("could not parse offset: %w", err)
+	}
+	return offset, true, nil
+}
+
+func (s *FileOffsetStore) path(consumerName string) string {
+	return filepath.Join(s.dir, url.PathEscape(consumerName)+".offset")
+}
@@ -0,0 +1,47 @@
+package bunnify
+
+import (
+	"testing"
+)
+
+func TestOffsetStores(t *testing.T) {
+	fileStore, err := NewFileOffsetStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stores := map[string]OffsetStore{
+		"Memory": NewMemoryOffsetStore(),
+		"File":   fileStore,
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			// Setup
+			consumerName := "orders/stream"
+
+			// Exercise & Assert
+			_, found, err := store.Load(consumerName)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if found {
+				t.Fatal("expected no offset before saving")
+			}
+
+			for _, offset := range []int64{5, 10, 7} {
+				if err := store.Save(consumerName, offset); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			offset, found, err := store.Load(consumerName)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !found || offset != 10 {
+				t.Fatalf("expected offset 10, got %d", offset)
+			}
+		})
+	}
+}
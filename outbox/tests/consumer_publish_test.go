@@ -17,13 +17,17 @@ import (
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/propagation"
 	tracesdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/goleak"
 )
 
 func TestOutboxWithAllAddsOn(t *testing.T) {
-	// Setup tracing
-	otel.SetTracerProvider(tracesdk.NewTracerProvider())
+	// Setup tracing, sampling every span as the outbox does not store the trace flags
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(tracesdk.NewTracerProvider(
+		tracesdk.WithSampler(tracesdk.AlwaysSample()),
+		tracesdk.WithSpanProcessor(recorder)))
 	otel.SetTextMapPropagator(propagation.TraceContext{})
 
 	// Setup notification channel
@@ -136,16 +140,33 @@ func TestOutboxWithAllAddsOn(t *testing.T) {
 	// Wait for event to be consumed
 	wg.Wait()
 
-	// Assert tracing data
-	expectedSpanID := trace.SpanFromContext(publisherCtx).SpanContext().SpanID()
-	if actualSpanID != expectedSpanID {
-		t.Fatalf("expected spanID %s, got %s", expectedSpanID, actualSpanID)
-	}
+	// Assert tracing data, the handler runs in a process span which parent is the publish span
 	expectedTraceID := trace.SpanFromContext(publisherCtx).SpanContext().TraceID()
 	if actualTraceID != expectedTraceID {
 		t.Fatalf("expected traceID %s, got %s", expectedTraceID, actualTraceID)
 	}
 
+	var publishSpan, processSpan tracesdk.ReadOnlySpan
+	for _, span := range recorder.Ended() {
+		switch {
+		case span.SpanKind() == trace.SpanKindProducer:
+			publishSpan = span
+		case span.SpanContext().SpanID() == actualSpanID:
+			processSpan = span
+		}
+	}
+	if publishSpan == nil || processSpan == nil {
+		t.Fatalf("expected publish and process spans, got %d spans", len(recorder.Ended()))
+	}
+
+	expectedSpanID := trace.SpanFromContext(publisherCtx).SpanContext().SpanID()
+	if publishSpan.Parent().SpanID() != expectedSpanID {
+		t.Fatalf("expected publish span parent %s, got %s", expectedSpanID, publishSpan.Parent().SpanID())
+	}
+	if processSpan.Parent().SpanID() != publishSpan.SpanContext().SpanID() {
+		t.Fatalf("expected process span parent %s, got %s", publishSpan.SpanContext().SpanID(), processSpan.Parent().SpanID())
+	}
+
 	// Assert event data
 	if orderCreatedID != consumedEvent.Payload.ID {
 		t.Fatalf("expected order created ID %s, got %s", orderCreatedID, consumedEvent.Payload.ID)
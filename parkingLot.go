@@ -0,0 +1,57 @@
+package bunnify
+
+import (
+	"fmt"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+const (
+	parkedReasonHeader = "x-bunnify-parked-reason"
+
+	parkReasonNotParsable     = "not_parsable"
+	parkReasonHandlerNotFound = "handler_not_found"
+
+	bodyPreviewLength = 128
+)
+
+// park sends an event that cannot be handled to the parking lot queue, keeping its body
+// and properties untouched and adding the reason as a header. Then the original is
+// acknowledged once the server confirms the copy. Without a parking lot queue, or if the copy
+// is not confirmed, the event is NACKed without requeue, so it goes to dead letter if there is one.
+// Requeueing it would handle it again straight away, failing the same way.
+func (c *Consumer) park(
+	channel *consumerChannel,
+	delivery amqp.Delivery,
+	deliveryInfo DeliveryInfo,
+	reason string,
+	err error) {
+
+	if c.options.parkingLotQueue == "" {
+		_ = delivery.Nack(false, false)
+		return
+	}
+
+	headers := retryHeaders(delivery, deliveryInfo, retryCount(delivery.Headers))
+	headers[parkedReasonHeader] = reason
+	if err != nil {
+		headers[errorMessageHeader] = err.Error()
+	}
+
+	if err := channel.republish("", c.options.parkingLotQueue, delivery, headers, ""); err != nil {
+		notifyEventRepublishFailed(c.options.notificationCh, deliveryInfo.RoutingKey, err)
+		_ = delivery.Nack(false, false)
+		return
+	}
+
+	_ = delivery.Ack(false)
+	notifyEventParked(c.options.notificationCh, deliveryInfo.RoutingKey, delivery.MessageId, bodyPreview(delivery.Body), c.options.parkingLotQueue, reason)
+}
+
+// bodyPreview returns the beginning of the body quoted, so it is safe to include in a notification.
+func bodyPreview(body []byte) string {
+	if len(body) <= bodyPreviewLength {
+		return fmt.Sprintf("%q", body)
+	}
+	return fmt.Sprintf("%q...", body[:bodyPreviewLength])
+}
@@ -0,0 +1,29 @@
+package bunnify
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBodyPreview(t *testing.T) {
+	t.Run("When body is short", func(t *testing.T) {
+		// Exercise
+		preview := bodyPreview([]byte("not json"))
+
+		// Assert
+		if preview != `"not json"` {
+			t.Fatalf("unexpected preview %s", preview)
+		}
+	})
+
+	t.Run("When body is longer than the preview", func(t *testing.T) {
+		// Exercise
+		preview := bodyPreview([]byte(strings.Repeat("a", bodyPreviewLength+10)))
+
+		// Assert
+		expected := `"` + strings.Repeat("a", bodyPreviewLength) + `"...`
+		if preview != expected {
+			t.Fatalf("unexpected preview %s", preview)
+		}
+	})
+}
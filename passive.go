@@ -0,0 +1,163 @@
+package bunnify
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// TopologyMismatchError is returned when verifying the topology, listing the entities
+// that do not exist on the server or that cannot be accessed, along with the expected definition.
+// When the management API is supplied, it also lists every property and argument that differs.
+type TopologyMismatchError struct {
+	Mismatches []TopologyMismatch
+}
+
+// TopologyMismatch describes an entity that did not pass the verification. Property is the
+// argument or property that differs, such as x-queue-type, or empty if the whole entity does.
+type TopologyMismatch struct {
+	Entity   string
+	Property string
+	Expected string
+	Actual   string
+}
+
+func (e *TopologyMismatchError) Error() string {
+	lines := make([]string, 0, len(e.Mismatches)*2+1)
+	lines = append(lines, fmt.Sprintf("topology does not match for %d entities:", len(e.Mismatches)))
+	for _, m := range e.Mismatches {
+		subject := m.Entity
+		if m.Property != "" {
+			subject = fmt.Sprintf("%s %s", m.Entity, m.Property)
+		}
+		lines = append(lines, fmt.Sprintf("- %s: %s", subject, m.Expected))
+		lines = append(lines, fmt.Sprintf("+ %s: %s", subject, m.Actual))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// verifyTopology checks with passive declarations that the entities exist. The server only
+// reports whether an entity exists and can be accessed, it does not compare the arguments.
+// When the management API is supplied, the properties, arguments and bindings of the
+// entities are read from it and compared one by one.
+func verifyTopology(steps []topologyStep, newChannel func() (*amqp.Channel, error), api *managementAPI) error {
+	mismatches := make([]TopologyMismatch, 0)
+	failed := make(map[string]bool)
+	runTopology(steps, true, newChannel, func(step topologyStep, err error) {
+		failed[step.entity] = true
+		mismatches = append(mismatches, TopologyMismatch{
+			Entity:   step.entity,
+			Expected: step.expected,
+			Actual:   describeFailure(err),
+		})
+	})
+
+	for _, step := range steps {
+		if api == nil || step.inspect == nil || failed[step.entity] {
+			continue
+		}
+
+		stepMismatches, err := step.inspect(api)
+		if err != nil {
+			stepMismatches = []TopologyMismatch{{Entity: step.entity, Expected: step.expected, Actual: err.Error()}}
+		}
+		mismatches = append(mismatches, stepMismatches...)
+	}
+
+	if len(mismatches) > 0 {
+		return &TopologyMismatchError{Mismatches: mismatches}
+	}
+	return nil
+}
+
+// topologySteps returns the exchanges and queues that the consumer declares.
+func (c *Consumer) topologySteps() []topologyStep {
+	steps := make([]topologyStep, 0)
+
+	if c.options.exchange != "" {
+		steps = append(steps, ExchangeDefinition{
+			Name:    c.options.exchange,
+			Kind:    c.options.exchangeKind,
+			Durable: true,
+		}.step())
+	}
+
+	if c.options.deadLetterQueue != "" {
+		steps = append(steps,
+			ExchangeDefinition{Name: c.deadLetterExchange(), Kind: ExchangeKindDirect, Durable: true}.step(),
+			QueueDefinition{Name: c.options.deadLetterQueue, Durable: true}.step())
+	}
+
+	for attempt := range c.options.retryDelays {
+		steps = append(steps, QueueDefinition{
+			Name:      c.retryQueueName(attempt),
+			Durable:   true,
+			Arguments: c.retryQueueArguments(attempt),
+		}.step())
+	}
+
+	if c.options.parkingLotQueue != "" {
+		steps = append(steps, QueueDefinition{Name: c.options.parkingLotQueue, Durable: true}.step())
+	}
+
+	steps = append(steps, QueueDefinition{
+		Name:      c.queueName,
+		Durable:   true,
+		Arguments: c.queueArguments(),
+	}.step())
+
+	return steps
+}
+
+// verifyTopology checks that the exchanges and queues of the consumer exist, without declaring them.
+func (c *Consumer) verifyTopology(steps []topologyStep) error {
+	return verifyTopology(steps, c.newTopologyChannel, c.options.managementAPI)
+}
+
+// newTopologyChannel opens a channel apart from the consuming one, as a failed declaration closes it.
+func (c *Consumer) newTopologyChannel() (*amqp.Channel, error) {
+	channel, connectionClosed := c.getNewChannel(c.stopSignal())
+	if connectionClosed {
+		return nil, errConnectionClosedByUser
+	}
+	if channel == nil {
+		return nil, errConsumerPaused
+	}
+	return channel, nil
+}
+
+// describeEntity returns a readable description of the expected entity.
+func describeEntity(durable bool, kind string, args amqp.Table) string {
+	description := kind
+	if durable {
+		description = "durable " + kind
+	}
+
+	if len(args) == 0 {
+		return description
+	}
+
+	keys := make([]string, 0, len(args))
+	for k := range args {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, fmt.Sprintf("%s=%v", k, args[k]))
+	}
+	return fmt.Sprintf("%s with %s", description, strings.Join(pairs, ", "))
+}
+
+// describeFailure returns the reason given by the server, such as NOT_FOUND or ACCESS_REFUSED.
+func describeFailure(err error) string {
+	var amqpErr *amqp.Error
+	if errors.As(err, &amqpErr) {
+		return amqpErr.Reason
+	}
+	return err.Error()
+}
@@ -0,0 +1,85 @@
+package bunnify
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+func TestConsumerTopologySteps(t *testing.T) {
+	// Setup
+	consumer := Consumer{&consumerCore{
+		queueName: "orders",
+		options: consumerOption{
+			exchange:        "events",
+			exchangeKind:    ExchangeKindTopic,
+			deadLetterQueue: "orders-dlq",
+			parkingLotQueue: "orders-parked",
+			retryDelays:     []time.Duration{time.Second},
+			quorumQueue:     true,
+		},
+	}}
+
+	// Exercise
+	steps := consumer.topologySteps()
+
+	// Assert
+	expected := map[string]string{
+		"exchange events":              "durable topic exchange",
+		"exchange orders-dlq-exchange": "durable direct exchange",
+		"queue orders-dlq":             "durable queue",
+		"queue orders-retry-1s":        "durable queue with x-dead-letter-exchange=, x-dead-letter-routing-key=orders, x-message-ttl=1000, x-queue-type=quorum",
+		"queue orders-parked":          "durable queue",
+		"queue orders":                 "durable queue with x-dead-letter-exchange=orders-dlq-exchange, x-dead-letter-routing-key=, x-queue-type=quorum",
+	}
+	if len(steps) != len(expected) {
+		t.Fatalf("expected %d steps, got %d", len(expected), len(steps))
+	}
+	for _, step := range steps {
+		if expected[step.entity] != step.expected {
+			t.Fatalf("expected %s to be %s, got %s", step.entity, expected[step.entity], step.expected)
+		}
+		if step.verify == nil {
+			t.Fatalf("expected %s to be verified", step.entity)
+		}
+	}
+}
+
+func TestTopologyMismatchError(t *testing.T) {
+	// Setup
+	err := &TopologyMismatchError{Mismatches: []TopologyMismatch{{
+		Entity:   "queue orders",
+		Expected: "durable queue with x-queue-type=quorum",
+		Actual:   describeFailure(&amqp.Error{Code: amqp.NotFound, Reason: "NOT_FOUND - no queue 'orders' in vhost '/'"}),
+	}}}
+
+	// Exercise
+	msg := err.Error()
+
+	// Assert
+	expected := "topology does not match for 1 entities:\n" +
+		"- queue orders: durable queue with x-queue-type=quorum\n" +
+		"+ queue orders: NOT_FOUND - no queue 'orders' in vhost '/'"
+	if msg != expected {
+		t.Fatalf("expected %s, got %s", expected, msg)
+	}
+}
+
+func TestVerifyTopology(t *testing.T) {
+	// Setup
+	steps := []topologyStep{
+		{entity: "binding", declare: func(channel *amqp.Channel) error { return nil }},
+	}
+
+	// Exercise
+	err := verifyTopology(steps, func() (*amqp.Channel, error) {
+		return nil, errors.New("no channel expected for steps that cannot be verified")
+	}, nil)
+
+	// Assert
+	if err != nil {
+		t.Fatal(err)
+	}
+}
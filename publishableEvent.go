@@ -17,6 +17,9 @@ type PublishableEvent struct {
 type eventOptions struct {
 	eventID       string
 	correlationID string
+	source        string
+	eventType     string
+	subject       string
 }
 
 // WithEventID specifies the eventID to be published
@@ -35,6 +38,30 @@ func WithCorrelationID(correlationID string) func(*eventOptions) {
 	}
 }
 
+// WithSource specifies the context in which the event happened.
+// It is sent as the CloudEvents source attribute.
+func WithSource(source string) func(*eventOptions) {
+	return func(opt *eventOptions) {
+		opt.source = source
+	}
+}
+
+// WithType specifies the type of the event. It is sent as the CloudEvents
+// type attribute, if it is not used the routing key will be used instead.
+func WithType(eventType string) func(*eventOptions) {
+	return func(opt *eventOptions) {
+		opt.eventType = eventType
+	}
+}
+
+// WithSubject specifies the subject of the event in the context of the source.
+// It is sent as the CloudEvents subject attribute.
+func WithSubject(subject string) func(*eventOptions) {
+	return func(opt *eventOptions) {
+		opt.subject = subject
+	}
+}
+
 // NewPublishableEvent creates an instance of a PublishableEvent.
 // In case the ID and correlation ID are not supplied via options random uuid will be generated.
 func NewPublishableEvent(payload any, opts ...func(*eventOptions)) PublishableEvent {
@@ -55,6 +82,9 @@ func NewPublishableEvent(payload any, opts ...func(*eventOptions)) PublishableEv
 			ID:            evtOpts.eventID,
 			CorrelationID: evtOpts.correlationID,
 			Timestamp:     time.Now(),
+			Source:        evtOpts.source,
+			Type:          evtOpts.eventType,
+			Subject:       evtOpts.subject,
 		},
 		Payload: payload,
 	}
@@ -5,20 +5,49 @@ import (
 	"encoding/json"
 	"fmt"
 	"sync"
+	"sync/atomic"
 
 	amqp "github.com/rabbitmq/amqp091-go"
+	"go.opentelemetry.io/otel/trace"
 )
 
+type publisherOption struct {
+	rawPayload     bool
+	eventFormat    EventFormat
+	tracerProvider trace.TracerProvider
+}
+
+// WithRawPayload specifies that events are published without the bunnify envelope,
+// so consumers not using bunnify receive the payload as the whole body.
+// The metadata is still sent as the message id, correlation id and timestamp properties.
+func WithRawPayload() func(*publisherOption) {
+	return func(opt *publisherOption) {
+		opt.rawPayload = true
+	}
+}
+
 // Publisher is used for publishing events.
 type Publisher struct {
 	mu            sync.Mutex
-	inUseChannel  *amqp.Channel
+	options       publisherOption
 	getNewChannel func() (*amqp.Channel, bool)
+
+	// inUseChannel is only replaced holding mu, but it is read without it by ChannelState
+	inUseChannel atomic.Pointer[amqp.Channel]
 }
 
 // NewPublisher creates a publisher using the specified connection.
-func (c *Connection) NewPublisher() *Publisher {
+func (c *Connection) NewPublisher(opts ...func(*publisherOption)) *Publisher {
+	options := publisherOption{
+		eventFormat:    c.options.eventFormat,
+		tracerProvider: c.options.tracerProvider,
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	return &Publisher{
+		options: options,
 		getNewChannel: func() (*amqp.Channel, bool) {
 			return c.getNewChannel(NotificationSourcePublisher)
 		},
@@ -27,37 +56,34 @@ func (c *Connection) NewPublisher() *Publisher {
 
 // Publish publishes an event to the specified exchange.
 // If the channel is closed, it will retry until a channel is obtained.
+// The publish is traced with a producer span, which context is sent with the event.
 func (p *Publisher) Publish(
 	ctx context.Context,
 	exchange, routingKey string,
-	event PublishableEvent) error {
+	event PublishableEvent) (err error) {
+
+	ctx, span := startPublishSpan(ctx, p.options.tracerProvider, exchange, routingKey, event)
+	defer func() { endSpan(span, err) }()
 
-	b, err := json.Marshal(event)
+	publishing, err := p.newPublishing(ctx, routingKey, event)
 	if err != nil {
 		return fmt.Errorf("could not marshal event: %w", err)
 	}
 
-	publishing := amqp.Publishing{
-		ContentEncoding: "application/json",
-		CorrelationId:   event.CorrelationID,
-		MessageId:       event.ID,
-		Timestamp:       event.Timestamp,
-		Body:            b,
-		Headers:         injectToHeaders(ctx),
-	}
-
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
-	if p.inUseChannel == nil || p.inUseChannel.IsClosed() {
-		channel, connectionClosed := p.getNewChannel()
+	channel := p.inUseChannel.Load()
+	if channel == nil || channel.IsClosed() {
+		var connectionClosed bool
+		channel, connectionClosed = p.getNewChannel()
 		if connectionClosed {
 			return fmt.Errorf("connection closed by system, channel will not reconnect")
 		}
-		p.inUseChannel = channel
+		p.inUseChannel.Store(channel)
 	}
 
-	err = p.inUseChannel.PublishWithContext(ctx, exchange, routingKey, true, false, publishing)
+	err = channel.PublishWithContext(ctx, exchange, routingKey, true, false, publishing)
 	if err != nil {
 		eventPublishFailed(exchange, routingKey)
 		return err
@@ -66,3 +92,61 @@ func (p *Publisher) Publish(
 	eventPublishSucceed(exchange, routingKey)
 	return nil
 }
+
+// ChannelState returns the state of the channel used for publishing.
+// The channel is opened on the first publish and reopened on the next one after being closed.
+func (p *Publisher) ChannelState() ChannelState {
+	channel := p.inUseChannel.Load()
+	switch {
+	case channel == nil:
+		return ChannelStateIdle
+	case channel.IsClosed():
+		return ChannelStateClosed
+	default:
+		return ChannelStateOpen
+	}
+}
+
+// newPublishing encodes the event following the event format of the publisher.
+// Raw payloads take precedence over the format, as they are sent without any envelope.
+func (p *Publisher) newPublishing(
+	ctx context.Context,
+	routingKey string,
+	event PublishableEvent) (amqp.Publishing, error) {
+
+	publishing := amqp.Publishing{
+		ContentEncoding: "application/json",
+		CorrelationId:   event.CorrelationID,
+		MessageId:       event.ID,
+		Timestamp:       event.Timestamp,
+		Headers:         injectToHeaders(ctx),
+	}
+
+	var err error
+	switch {
+	case p.options.rawPayload:
+		publishing.ContentType = "application/json"
+		publishing.Body, err = json.Marshal(event.Payload)
+	case p.options.eventFormat == EventFormatCloudEventsBinary:
+		var ce cloudEvent
+		if ce, err = newCloudEvent(routingKey, event); err != nil {
+			return publishing, err
+		}
+		for k, v := range ce.binaryHeaders() {
+			publishing.Headers[k] = v
+		}
+		publishing.ContentType = ce.DataContentType
+		publishing.Body = ce.Data
+	case p.options.eventFormat == EventFormatCloudEventsStructured:
+		var ce cloudEvent
+		if ce, err = newCloudEvent(routingKey, event); err != nil {
+			return publishing, err
+		}
+		publishing.ContentType = cloudEventsContentType
+		publishing.Body, err = json.Marshal(ce)
+	default:
+		publishing.Body, err = json.Marshal(event)
+	}
+
+	return publishing, err
+}
@@ -0,0 +1,103 @@
+package bunnify
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// validateQueueArguments checks the queue arguments before declaring
+// the queue, as the server would close the channel otherwise.
+func (c *Consumer) validateQueueArguments() error {
+	errs := make([]error, 0)
+	opts := c.options
+
+	if opts.messageTTL < 0 {
+		errs = append(errs, fmt.Errorf("message TTL cannot be negative, got %s", opts.messageTTL))
+	}
+
+	if opts.maxLength < 0 {
+		errs = append(errs, fmt.Errorf("max length cannot be negative, got %d", opts.maxLength))
+	}
+
+	if opts.maxLengthBytes < 0 {
+		errs = append(errs, fmt.Errorf("max length bytes cannot be negative, got %d", opts.maxLengthBytes))
+	}
+
+	switch opts.overflow {
+	case "", OverflowDropHead, OverflowRejectPublish:
+	case OverflowRejectPublishDLX:
+		if opts.quorumQueue {
+			errs = append(errs, fmt.Errorf("overflow %s is not supported by quorum queues", opts.overflow))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("unknown overflow %s", opts.overflow))
+	}
+
+	if opts.overflow != "" && opts.maxLength == 0 && opts.maxLengthBytes == 0 {
+		errs = append(errs, fmt.Errorf("overflow requires max length or max length bytes"))
+	}
+
+	if opts.maxPriority != 0 {
+		if opts.maxPriority < 1 || opts.maxPriority > 255 {
+			errs = append(errs, fmt.Errorf("max priority must be between 1 and 255, got %d", opts.maxPriority))
+		}
+		if opts.quorumQueue {
+			errs = append(errs, fmt.Errorf("max priority is not supported by quorum queues"))
+		}
+	}
+
+	if opts.deliveryLimit != 0 {
+		if opts.deliveryLimit < 0 {
+			errs = append(errs, fmt.Errorf("delivery limit cannot be negative, got %d", opts.deliveryLimit))
+		}
+		if !opts.quorumQueue {
+			errs = append(errs, fmt.Errorf("delivery limit is only supported by quorum queues"))
+		}
+	}
+
+	if opts.streamQueue {
+		if opts.messageTTL != 0 || opts.maxLength != 0 || opts.overflow != "" || opts.maxPriority != 0 {
+			errs = append(errs, fmt.Errorf("streams only support max length bytes"))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// setQueueArguments adds the optional queue arguments to the table used to declare the queue.
+func (c *Consumer) setQueueArguments(amqpTable amqp.Table) {
+	opts := c.options
+
+	if opts.messageTTL > 0 {
+		amqpTable["x-message-ttl"] = ttlMilliseconds(opts.messageTTL)
+	}
+
+	if opts.maxLength > 0 {
+		amqpTable["x-max-length"] = int64(opts.maxLength)
+	}
+
+	if opts.maxLengthBytes > 0 {
+		amqpTable["x-max-length-bytes"] = int64(opts.maxLengthBytes)
+	}
+
+	if opts.overflow != "" {
+		amqpTable["x-overflow"] = string(opts.overflow)
+	}
+
+	if opts.maxPriority > 0 {
+		amqpTable["x-max-priority"] = int64(opts.maxPriority)
+	}
+
+	if opts.deliveryLimit > 0 {
+		amqpTable["x-delivery-limit"] = int64(opts.deliveryLimit)
+	}
+}
+
+// ttlMilliseconds converts the duration to the whole milliseconds used by TTLs and expirations.
+// It is rounded up, so a duration under a millisecond does not become 0 and expire the event at once.
+func ttlMilliseconds(d time.Duration) int64 {
+	return max(int64((d+time.Millisecond-1)/time.Millisecond), 1)
+}
@@ -0,0 +1,91 @@
+package bunnify
+
+import (
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+func TestValidateQueueArguments(t *testing.T) {
+	cases := []struct {
+		name    string
+		options consumerOption
+		valid   bool
+	}{
+		{"Classic queue with every argument", consumerOption{
+			messageTTL: time.Minute, maxLength: 10, maxLengthBytes: 1024,
+			overflow: OverflowRejectPublishDLX, maxPriority: 10,
+		}, true},
+		{"Quorum queue with delivery limit", consumerOption{quorumQueue: true, deliveryLimit: 5}, true},
+		{"Classic queue with delivery limit", consumerOption{deliveryLimit: 5}, false},
+		{"Quorum queue with max priority", consumerOption{quorumQueue: true, maxPriority: 5}, false},
+		{"Quorum queue with reject publish dlx", consumerOption{quorumQueue: true, maxLength: 1, overflow: OverflowRejectPublishDLX}, false},
+		{"Overflow without max length", consumerOption{overflow: OverflowRejectPublish}, false},
+		{"Unknown overflow", consumerOption{maxLength: 1, overflow: "unknown"}, false},
+		{"Max priority out of range", consumerOption{maxPriority: 256}, false},
+		{"Negative message TTL", consumerOption{messageTTL: -time.Second}, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			consumer := Consumer{&consumerCore{options: tc.options}}
+			err := consumer.validateQueueArguments()
+			if tc.valid && err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+			if !tc.valid && err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
+
+func TestSetQueueArguments(t *testing.T) {
+	// Setup
+	consumer := Consumer{&consumerCore{options: consumerOption{
+		messageTTL:     time.Minute,
+		maxLength:      10,
+		maxLengthBytes: 1024,
+		overflow:       OverflowRejectPublish,
+		maxPriority:    5,
+	}}}
+
+	// Exercise
+	amqpTable := amqp.Table{}
+	consumer.setQueueArguments(amqpTable)
+
+	// Assert
+	expected := amqp.Table{
+		"x-message-ttl":      int64(60000),
+		"x-max-length":       int64(10),
+		"x-max-length-bytes": int64(1024),
+		"x-overflow":         "reject-publish",
+		"x-max-priority":     int64(5),
+	}
+	for k, v := range expected {
+		if amqpTable[k] != v {
+			t.Fatalf("expected %s to be %v, got %v", k, v, amqpTable[k])
+		}
+	}
+	if _, ok := amqpTable["x-delivery-limit"]; ok {
+		t.Fatal("expected no delivery limit")
+	}
+}
+
+func TestTTLMilliseconds(t *testing.T) {
+	cases := map[time.Duration]int64{
+		time.Microsecond:                   1,
+		time.Millisecond:                   1,
+		time.Millisecond + time.Nanosecond: 2,
+		1500 * time.Microsecond:            2,
+		time.Minute:                        60000,
+		0:                                  1,
+	}
+
+	for d, expected := range cases {
+		if actual := ttlMilliseconds(d); actual != expected {
+			t.Fatalf("expected %s to be %d milliseconds, got %d", d, expected, actual)
+		}
+	}
+}
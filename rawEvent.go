@@ -0,0 +1,36 @@
+package bunnify
+
+import (
+	"encoding/json"
+	"errors"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// decodeEvent returns the event contained in the delivery. Events published by bunnify are
+// wrapped in an envelope with the metadata and the payload, unless they are CloudEvents.
+// Raw events are not, so the whole body is the payload and the metadata is taken from the AMQP properties.
+func decodeEvent(delivery amqp.Delivery, deliveryInfo DeliveryInfo, raw bool) (unmarshalEvent, error) {
+	uevt := unmarshalEvent{DeliveryInfo: deliveryInfo}
+
+	if !raw && isCloudEvent(delivery) {
+		return decodeCloudEvent(delivery, deliveryInfo)
+	}
+
+	if !raw {
+		err := json.Unmarshal(delivery.Body, &uevt)
+		return uevt, err
+	}
+
+	if !json.Valid(delivery.Body) {
+		return uevt, errors.New("body is not valid json")
+	}
+
+	uevt.Metadata = Metadata{
+		ID:            delivery.MessageId,
+		CorrelationID: delivery.CorrelationId,
+		Timestamp:     delivery.Timestamp,
+	}
+	uevt.Payload = delivery.Body
+	return uevt, nil
+}
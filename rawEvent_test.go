@@ -0,0 +1,86 @@
+package bunnify
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+func TestDecodeEvent(t *testing.T) {
+	t.Run("When event is wrapped in the envelope", func(t *testing.T) {
+		// Exercise
+		uevt, err := decodeEvent(amqp.Delivery{
+			Body: []byte(`{"id":"1","correlationId":"2","payload":{"name":"order"}}`),
+		}, DeliveryInfo{Queue: "queue"}, false)
+
+		// Assert
+		if err != nil {
+			t.Fatal(err)
+		}
+		if uevt.ID != "1" || uevt.CorrelationID != "2" {
+			t.Fatalf("unexpected metadata %+v", uevt.Metadata)
+		}
+		if string(uevt.Payload) != `{"name":"order"}` {
+			t.Fatalf("unexpected payload %s", uevt.Payload)
+		}
+		if uevt.DeliveryInfo.Queue != "queue" {
+			t.Fatalf("unexpected queue %s", uevt.DeliveryInfo.Queue)
+		}
+	})
+
+	t.Run("When event is raw", func(t *testing.T) {
+		// Setup
+		timestamp := time.Now()
+
+		// Exercise
+		uevt, err := decodeEvent(amqp.Delivery{
+			MessageId:     "1",
+			CorrelationId: "2",
+			Timestamp:     timestamp,
+			Body:          []byte(`[1,2,3]`),
+		}, DeliveryInfo{}, true)
+
+		// Assert
+		if err != nil {
+			t.Fatal(err)
+		}
+		if uevt.ID != "1" || uevt.CorrelationID != "2" || !uevt.Timestamp.Equal(timestamp) {
+			t.Fatalf("unexpected metadata %+v", uevt.Metadata)
+		}
+		if string(uevt.Payload) != `[1,2,3]` {
+			t.Fatalf("unexpected payload %s", uevt.Payload)
+		}
+	})
+
+	t.Run("When raw event is not json", func(t *testing.T) {
+		// Exercise
+		_, err := decodeEvent(amqp.Delivery{Body: []byte("not json")}, DeliveryInfo{}, true)
+
+		// Assert
+		if err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
+
+func TestRawPublishing(t *testing.T) {
+	// Setup
+	publisher := Publisher{options: publisherOption{rawPayload: true}}
+	event := NewPublishableEvent(map[string]string{"id": "order"})
+
+	// Exercise
+	publishing, err := publisher.newPublishing(context.TODO(), "order.created", event)
+
+	// Assert
+	if err != nil {
+		t.Fatal(err)
+	}
+	if publishing.ContentType != "application/json" {
+		t.Fatalf("expected content type application/json, got %s", publishing.ContentType)
+	}
+	if string(publishing.Body) != `{"id":"order"}` {
+		t.Fatalf("unexpected body %s", publishing.Body)
+	}
+}
@@ -0,0 +1,269 @@
+package bunnify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+type redriveOption struct {
+	routingKeys []string
+	minAge      time.Duration
+	maxAge      time.Duration
+	limit       int
+	ratePerSec  int
+	dryRun      bool
+}
+
+// WithRedriveRoutingKeys specifies that only the events with one of
+// the original routing keys indicated will be redriven.
+func WithRedriveRoutingKeys(routingKeys ...string) func(*redriveOption) {
+	return func(opt *redriveOption) {
+		opt.routingKeys = routingKeys
+	}
+}
+
+// WithRedriveMinAge specifies that only the events dead lettered
+// at least the indicated duration ago will be redriven.
+func WithRedriveMinAge(age time.Duration) func(*redriveOption) {
+	return func(opt *redriveOption) {
+		opt.minAge = age
+	}
+}
+
+// WithRedriveMaxAge specifies that only the events dead lettered
+// at most the indicated duration ago will be redriven.
+func WithRedriveMaxAge(age time.Duration) func(*redriveOption) {
+	return func(opt *redriveOption) {
+		opt.maxAge = age
+	}
+}
+
+// WithRedriveLimit specifies the maximum amount of events to redrive.
+func WithRedriveLimit(limit int) func(*redriveOption) {
+	return func(opt *redriveOption) {
+		opt.limit = limit
+	}
+}
+
+// WithRedriveRate specifies the maximum amount of events redriven per second.
+func WithRedriveRate(perSecond int) func(*redriveOption) {
+	return func(opt *redriveOption) {
+		opt.ratePerSec = perSecond
+	}
+}
+
+// WithRedriveDryRun specifies that the events will not be republished nor removed
+// from the dead letter queue, only counted as if they were redriven.
+func WithRedriveDryRun() func(*redriveOption) {
+	return func(opt *redriveOption) {
+		opt.dryRun = true
+	}
+}
+
+// RedriveResult holds the amount of events processed by Redrive.
+type RedriveResult struct {
+	// Redriven are the events republished, or that would be republished on a dry run.
+	Redriven int
+	// Skipped are the events that did not match the filters and were kept on the dead letter queue.
+	Skipped int
+	// Failed are the events that could not be republished and were kept on the dead letter queue.
+	Failed int
+}
+
+// Redrive reads the events from a dead letter queue and republishes them to the exchange and
+// routing key they had before being dead lettered. An event is only removed from the dead letter
+// queue after the server confirms the republish. The events that are skipped or fail are kept
+// on the queue. The redrive stops when the queue is empty, the limit is reached or the context is done.
+// Take into account that other queues bound with the same routing key will receive the event again.
+func (c *Connection) Redrive(
+	ctx context.Context,
+	deadLetterQueue string,
+	opts ...func(*redriveOption)) (RedriveResult, error) {
+
+	options := redriveOption{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	result := RedriveResult{}
+
+	channel, connectionClosed := c.getNewChannel(NotificationSourceConsumer)
+	if connectionClosed {
+		return result, errConnectionClosedByUser
+	}
+	defer channel.Close()
+
+	if !options.dryRun {
+		if err := channel.Confirm(false); err != nil {
+			return result, fmt.Errorf("failed to enable publisher confirms: %w", err)
+		}
+	}
+	returns := channel.NotifyReturn(make(chan amqp.Return, 1))
+
+	var ticker *time.Ticker
+	if options.ratePerSec > 0 {
+		ticker = time.NewTicker(time.Second / time.Duration(options.ratePerSec))
+		defer ticker.Stop()
+	}
+
+	// Events kept on the queue stay unacknowledged until the end so that
+	// they are not obtained again, then they are requeued all at once.
+	kept := make([]amqp.Delivery, 0)
+	defer func() {
+		for _, delivery := range kept {
+			_ = delivery.Nack(false, true)
+		}
+	}()
+
+	for options.limit <= 0 || result.Redriven < options.limit {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		delivery, ok, err := channel.Get(deadLetterQueue, false)
+		if err != nil {
+			return result, fmt.Errorf("failed to get from dead letter queue: %w", err)
+		}
+		if !ok {
+			return result, nil
+		}
+
+		deliveryInfo := getDeliveryInfo(deadLetterQueue, delivery)
+		if !options.matches(deliveryInfo, delivery) {
+			kept = append(kept, delivery)
+			result.Skipped++
+			continue
+		}
+
+		if options.dryRun {
+			kept = append(kept, delivery)
+			result.Redriven++
+			continue
+		}
+
+		if ticker != nil {
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				kept = append(kept, delivery)
+				return result, ctx.Err()
+			}
+		}
+
+		if err := redrivePublish(ctx, channel, returns, deliveryInfo, delivery); err != nil {
+			kept = append(kept, delivery)
+			result.Failed++
+			notifyEventRedriveFailed(c.options.notificationChannel, deliveryInfo.RoutingKey, err)
+			continue
+		}
+
+		if err := delivery.Ack(false); err != nil {
+			return result, fmt.Errorf("failed to acknowledge redriven event: %w", err)
+		}
+		result.Redriven++
+	}
+
+	return result, nil
+}
+
+func (o redriveOption) matches(deliveryInfo DeliveryInfo, delivery amqp.Delivery) bool {
+	if len(o.routingKeys) > 0 && !slices.Contains(o.routingKeys, deliveryInfo.RoutingKey) {
+		return false
+	}
+
+	if o.minAge <= 0 && o.maxAge <= 0 {
+		return true
+	}
+
+	deadLetteredAt, ok := deathTime(delivery.Headers)
+	if !ok {
+		return false
+	}
+
+	age := time.Since(deadLetteredAt)
+	if o.minAge > 0 && age < o.minAge {
+		return false
+	}
+	if o.maxAge > 0 && age > o.maxAge {
+		return false
+	}
+	return true
+}
+
+// redrivePublish publishes the event to the original exchange and routing key and waits
+// for the server confirmation. Events that cannot be routed are considered failed.
+func redrivePublish(
+	ctx context.Context,
+	channel *amqp.Channel,
+	returns <-chan amqp.Return,
+	deliveryInfo DeliveryInfo,
+	delivery amqp.Delivery) error {
+
+	headers := amqp.Table{}
+	for k, v := range delivery.Headers {
+		if k != "x-death" && !strings.HasPrefix(k, "x-bunnify-") {
+			headers[k] = v
+		}
+	}
+
+	confirmation, err := channel.PublishWithDeferredConfirmWithContext(
+		ctx,
+		deliveryInfo.Exchange,
+		deliveryInfo.RoutingKey,
+		true,  // mandatory
+		false, // immediate
+		amqp.Publishing{
+			Headers:         headers,
+			ContentType:     delivery.ContentType,
+			ContentEncoding: delivery.ContentEncoding,
+			DeliveryMode:    delivery.DeliveryMode,
+			Priority:        delivery.Priority,
+			CorrelationId:   delivery.CorrelationId,
+			ReplyTo:         delivery.ReplyTo,
+			MessageId:       delivery.MessageId,
+			Timestamp:       delivery.Timestamp,
+			Type:            delivery.Type,
+			AppId:           delivery.AppId,
+			Body:            delivery.Body,
+		})
+	if err != nil {
+		return err
+	}
+
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		return err
+	}
+	if !acked {
+		return errors.New("republish was not confirmed by the server")
+	}
+
+	// The server sends the return before the confirmation
+	select {
+	case r := <-returns:
+		return fmt.Errorf("event could not be routed to %s with routing key %s: %s", r.Exchange, r.RoutingKey, r.ReplyText)
+	default:
+		return nil
+	}
+}
+
+// deathTime returns when the event was dead lettered for the last time. Events dead lettered
+// with failure details are published by bunnify, so they have no x-death and the failure time is used instead.
+func deathTime(headers amqp.Table) (time.Time, bool) {
+	deaths := getDeaths(headers)
+	if len(deaths) > 0 && !deaths[0].Time.IsZero() {
+		return deaths[0].Time, true
+	}
+
+	failedAt, ok := headers[failedAtHeader].(time.Time)
+	if !ok || failedAt.IsZero() {
+		return time.Time{}, false
+	}
+	return failedAt, true
+}
@@ -0,0 +1,52 @@
+package bunnify
+
+import (
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+func TestRedriveOptionMatches(t *testing.T) {
+	deadLetteredAgo := func(ago time.Duration) amqp.Delivery {
+		return amqp.Delivery{
+			Headers: amqp.Table{
+				"x-death": []interface{}{
+					amqp.Table{"time": time.Now().Add(-ago)},
+				},
+			},
+		}
+	}
+
+	failedAgo := func(ago time.Duration) amqp.Delivery {
+		return amqp.Delivery{
+			Headers: amqp.Table{failedAtHeader: time.Now().Add(-ago)},
+		}
+	}
+
+	cases := []struct {
+		name     string
+		options  redriveOption
+		info     DeliveryInfo
+		delivery amqp.Delivery
+		expected bool
+	}{
+		{"No filters", redriveOption{}, DeliveryInfo{RoutingKey: "a"}, amqp.Delivery{}, true},
+		{"Routing key matches", redriveOption{routingKeys: []string{"a", "b"}}, DeliveryInfo{RoutingKey: "b"}, amqp.Delivery{}, true},
+		{"Routing key does not match", redriveOption{routingKeys: []string{"a"}}, DeliveryInfo{RoutingKey: "c"}, amqp.Delivery{}, false},
+		{"Old enough", redriveOption{minAge: time.Minute}, DeliveryInfo{}, deadLetteredAgo(time.Hour), true},
+		{"Not old enough", redriveOption{minAge: time.Hour}, DeliveryInfo{}, deadLetteredAgo(time.Minute), false},
+		{"Too old", redriveOption{maxAge: time.Minute}, DeliveryInfo{}, deadLetteredAgo(time.Hour), false},
+		{"Age filter without death time", redriveOption{maxAge: time.Minute}, DeliveryInfo{}, amqp.Delivery{}, false},
+		{"Failed old enough", redriveOption{minAge: time.Minute}, DeliveryInfo{}, failedAgo(time.Hour), true},
+		{"Failed too recently", redriveOption{minAge: time.Hour}, DeliveryInfo{}, failedAgo(time.Minute), false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := tc.options.matches(tc.info, tc.delivery); actual != tc.expected {
+				t.Fatalf("expected match to be %t", tc.expected)
+			}
+		})
+	}
+}
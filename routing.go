@@ -0,0 +1,196 @@
+package bunnify
+
+import (
+	"reflect"
+	"strings"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// headerHandler is a handler selected by the headers of the event instead of the routing key.
+type headerHandler struct {
+	match   HeaderMatch
+	headers map[string]any
+	handler wrappedHandler
+}
+
+// bindingArgs returns the arguments used to bind the queue to a headers exchange.
+func (h headerHandler) bindingArgs() amqp.Table {
+	args := amqp.Table{"x-match": string(h.match)}
+	for k, v := range h.headers {
+		args[k] = v
+	}
+	return args
+}
+
+// matches returns true if all or any of the expected headers are present with the same value.
+func (h headerHandler) matches(headers amqp.Table) bool {
+	matched := 0
+	for k, expected := range h.headers {
+		actual, ok := headers[k]
+		if ok && headerValueEquals(expected, actual) {
+			matched++
+		}
+	}
+
+	if h.match == HeaderMatchAny {
+		return matched > 0
+	}
+	return matched == len(h.headers)
+}
+
+// getHandler returns the handler for the given routing key and headers. An exact routing key
+// match always wins. When consuming from a topic exchange, the handlers registered with a pattern
+// are checked next and the most specific matching pattern is used. Lastly, the header handlers
+// are checked in the order they were added. It also returns whether the handler expects raw events.
+func (c *Consumer) getHandler(routingKey string, headers amqp.Table) (handler wrappedHandler, raw bool, ok bool) {
+	if handler, ok := c.options.handlers[routingKey]; ok {
+		_, raw := c.options.rawHandlers[routingKey]
+		return handler, raw, true
+	}
+
+	if c.options.exchangeKind == ExchangeKindTopic {
+		if pattern, handler, ok := c.getPatternHandler(routingKey); ok {
+			_, raw := c.options.rawHandlers[pattern]
+			return handler, raw, true
+		}
+	}
+
+	for _, h := range c.options.headerHandlers {
+		if h.matches(headers) {
+			return h.handler, false, true
+		}
+	}
+
+	return nil, false, false
+}
+
+// getPatternHandler returns the handler registered with the most specific
+// topic pattern that matches the routing key, along with the pattern.
+func (c *Consumer) getPatternHandler(routingKey string) (string, wrappedHandler, bool) {
+	bestPattern := ""
+	var bestHandler wrappedHandler
+	for pattern, handler := range c.options.handlers {
+		if !isTopicPattern(pattern) || !topicMatches(pattern, routingKey) {
+			continue
+		}
+		if bestHandler == nil || moreSpecific(pattern, bestPattern) {
+			bestPattern = pattern
+			bestHandler = handler
+		}
+	}
+
+	return bestPattern, bestHandler, bestHandler != nil
+}
+
+// getBatchHandler returns the batch handler for the given routing key, along with the key it was
+// registered with, so the events matching the same pattern are accumulated in the same batch.
+// Batch handlers are matched like the others: an exact routing key wins, then, when consuming
+// from a topic exchange, the most specific pattern unless a handler has a more specific one.
+func (c *Consumer) getBatchHandler(routingKey string) (string, batchHandler, bool) {
+	if batch, ok := c.options.batchHandlers[routingKey]; ok {
+		return routingKey, batch, true
+	}
+
+	if c.options.exchangeKind != ExchangeKindTopic {
+		return "", batchHandler{}, false
+	}
+	if _, ok := c.options.handlers[routingKey]; ok {
+		return "", batchHandler{}, false
+	}
+
+	bestPattern, found := "", false
+	var bestBatch batchHandler
+	for pattern, batch := range c.options.batchHandlers {
+		if !isTopicPattern(pattern) || !topicMatches(pattern, routingKey) {
+			continue
+		}
+		if !found || moreSpecific(pattern, bestPattern) {
+			bestPattern, bestBatch, found = pattern, batch, true
+		}
+	}
+	if !found {
+		return "", batchHandler{}, false
+	}
+
+	if pattern, _, ok := c.getPatternHandler(routingKey); ok && moreSpecific(pattern, bestPattern) {
+		return "", batchHandler{}, false
+	}
+	return bestPattern, bestBatch, true
+}
+
+// isTopicPattern returns true if the routing key contains a topic wildcard word.
+func isTopicPattern(routingKey string) bool {
+	for _, word := range strings.Split(routingKey, ".") {
+		if word == "*" || word == "#" {
+			return true
+		}
+	}
+	return false
+}
+
+// topicMatches returns true if the routing key matches the pattern following the topic
+// exchange rules: * matches exactly one word and # matches zero or more words.
+func topicMatches(pattern, routingKey string) bool {
+	return matchWords(strings.Split(pattern, "."), strings.Split(routingKey, "."))
+}
+
+func matchWords(pattern, words []string) bool {
+	if len(pattern) == 0 {
+		return len(words) == 0
+	}
+
+	switch pattern[0] {
+	case "#":
+		for i := 0; i <= len(words); i++ {
+			if matchWords(pattern[1:], words[i:]) {
+				return true
+			}
+		}
+		return false
+	case "*":
+		return len(words) > 0 && matchWords(pattern[1:], words[1:])
+	default:
+		return len(words) > 0 && pattern[0] == words[0] && matchWords(pattern[1:], words[1:])
+	}
+}
+
+// moreSpecific returns true if pattern a should be preferred over pattern b.
+// Patterns with more literal words win, then the ones with less # wildcards.
+// Ties are broken alphabetically so that the dispatch is deterministic.
+func moreSpecific(a, b string) bool {
+	literalsA, hashesA := patternWeight(a)
+	literalsB, hashesB := patternWeight(b)
+
+	if literalsA != literalsB {
+		return literalsA > literalsB
+	}
+	if hashesA != hashesB {
+		return hashesA < hashesB
+	}
+	return a < b
+}
+
+func patternWeight(pattern string) (literals int, hashes int) {
+	for _, word := range strings.Split(pattern, ".") {
+		switch word {
+		case "#":
+			hashes++
+		case "*":
+		default:
+			literals++
+		}
+	}
+	return literals, hashes
+}
+
+// headerValueEquals compares header values, integers are compared
+// regardless of the width they were encoded with.
+func headerValueEquals(expected, actual any) bool {
+	e, eok := intValue(expected)
+	a, aok := intValue(actual)
+	if eok && aok {
+		return e == a
+	}
+	return reflect.DeepEqual(expected, actual)
+}
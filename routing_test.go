@@ -0,0 +1,161 @@
+package bunnify
+
+import (
+	"context"
+	"testing"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+func TestTopicMatches(t *testing.T) {
+	cases := []struct {
+		pattern    string
+		routingKey string
+		expected   bool
+	}{
+		{"order.*", "order.created", true},
+		{"order.*", "order.created.v2", false},
+		{"order.*", "order", false},
+		{"order.#", "order", true},
+		{"order.#", "order.created.v2", true},
+		{"#", "order.created", true},
+		{"*.created", "order.created", true},
+		{"*.created", "order.updated", false},
+		{"audit.#.v2", "audit.order.created.v2", true},
+		{"audit.#.v2", "audit.v2", true},
+		{"audit.#.v2", "audit.order.v1", false},
+	}
+
+	for _, tc := range cases {
+		if actual := topicMatches(tc.pattern, tc.routingKey); actual != tc.expected {
+			t.Fatalf("expected %s matching %s to be %t", tc.pattern, tc.routingKey, tc.expected)
+		}
+	}
+}
+
+func TestGetHandler(t *testing.T) {
+	// Setup
+	handlerFor := func(name string, invoked *string) wrappedHandler {
+		return func(ctx context.Context, event unmarshalEvent) error {
+			*invoked = name
+			return nil
+		}
+	}
+
+	var invoked string
+	consumer := Consumer{&consumerCore{
+		options: consumerOption{
+			exchangeKind: ExchangeKindTopic,
+			handlers: map[string]wrappedHandler{
+				"order.created": handlerFor("order.created", &invoked),
+				"order.*":       handlerFor("order.*", &invoked),
+				"order.#":       handlerFor("order.#", &invoked),
+				"#":             handlerFor("#", &invoked),
+			},
+		},
+	}}
+
+	cases := map[string]string{
+		"order.created":    "order.created",
+		"order.updated":    "order.*",
+		"order.updated.v2": "order.#",
+		"audit.login":      "#",
+	}
+
+	for routingKey, expected := range cases {
+		// Exercise
+		handler, _, ok := consumer.getHandler(routingKey, nil)
+		if !ok {
+			t.Fatalf("expected handler for %s", routingKey)
+		}
+		_ = handler(context.TODO(), unmarshalEvent{})
+
+		// Assert
+		if invoked != expected {
+			t.Fatalf("expected handler %s for %s, got %s", expected, routingKey, invoked)
+		}
+	}
+
+	t.Run("When exchange is not topic patterns are literal", func(t *testing.T) {
+		consumer.options.exchangeKind = ExchangeKindDirect
+		if _, _, ok := consumer.getHandler("order.updated", nil); ok {
+			t.Fatal("expected no handler for order.updated")
+		}
+	})
+}
+
+func TestGetBatchHandler(t *testing.T) {
+	// Setup
+	consumer := Consumer{&consumerCore{
+		options: consumerOption{
+			exchangeKind: ExchangeKindTopic,
+			handlers: map[string]wrappedHandler{
+				"order.created":   func(context.Context, unmarshalEvent) error { return nil },
+				"order.shipped.*": func(context.Context, unmarshalEvent) error { return nil },
+			},
+			batchHandlers: map[string]batchHandler{
+				"order.*":   {size: 1},
+				"order.#":   {size: 2},
+				"audit.log": {size: 3},
+			},
+		},
+	}}
+
+	cases := []struct {
+		routingKey string
+		expected   string
+		found      bool
+	}{
+		{"audit.log", "audit.log", true},
+		{"order.updated", "order.*", true},
+		{"order.updated.v2", "order.#", true},
+		{"order.created", "", false},
+		{"order.shipped.v2", "", false},
+		{"audit.other", "", false},
+	}
+
+	for _, tc := range cases {
+		// Exercise
+		key, _, ok := consumer.getBatchHandler(tc.routingKey)
+
+		// Assert
+		if ok != tc.found || key != tc.expected {
+			t.Fatalf("expected batch handler %q (%t) for %s, got %q (%t)", tc.expected, tc.found, tc.routingKey, key, ok)
+		}
+	}
+
+	t.Run("When exchange is not topic patterns are literal", func(t *testing.T) {
+		consumer.options.exchangeKind = ExchangeKindDirect
+		if _, _, ok := consumer.getBatchHandler("order.updated"); ok {
+			t.Fatal("expected no batch handler for order.updated")
+		}
+	})
+}
+
+func TestHeaderHandlerMatches(t *testing.T) {
+	headers := amqp.Table{
+		"type":     "order.created",
+		"tenant":   "acme",
+		"priority": int32(2),
+	}
+
+	cases := []struct {
+		name     string
+		handler  headerHandler
+		expected bool
+	}{
+		{"All headers match", headerHandler{match: HeaderMatchAll, headers: map[string]any{"type": "order.created", "tenant": "acme"}}, true},
+		{"Not all headers match", headerHandler{match: HeaderMatchAll, headers: map[string]any{"type": "order.created", "tenant": "other"}}, false},
+		{"Any header matches", headerHandler{match: HeaderMatchAny, headers: map[string]any{"type": "order.updated", "tenant": "acme"}}, true},
+		{"No header matches", headerHandler{match: HeaderMatchAny, headers: map[string]any{"region": "eu"}}, false},
+		{"Integers of different width match", headerHandler{match: HeaderMatchAll, headers: map[string]any{"priority": 2}}, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := tc.handler.matches(headers); actual != tc.expected {
+				t.Fatalf("expected match to be %t", tc.expected)
+			}
+		})
+	}
+}
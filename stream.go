@@ -0,0 +1,195 @@
+package bunnify
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// StreamOffset indicates from where a stream consumer starts reading.
+type StreamOffset struct {
+	value any
+}
+
+var (
+	// StreamOffsetFirst starts from the first event available in the stream.
+	StreamOffsetFirst = StreamOffset{value: "first"}
+	// StreamOffsetLast starts from the last chunk of events written to the stream.
+	StreamOffsetLast = StreamOffset{value: "last"}
+	// StreamOffsetNext starts from the next event published to the stream.
+	StreamOffsetNext = StreamOffset{value: "next"}
+)
+
+// StreamOffsetAt starts from the event with the given offset.
+func StreamOffsetAt(offset int64) StreamOffset {
+	return StreamOffset{value: offset}
+}
+
+// StreamOffsetFrom starts from the events published at the given time.
+func StreamOffsetFrom(timestamp time.Time) StreamOffset {
+	return StreamOffset{value: timestamp}
+}
+
+// validateStream checks that the options used are supported by streams.
+func (c *Consumer) validateStream() error {
+	if !c.options.streamQueue {
+		return nil
+	}
+
+	errs := make([]error, 0)
+	opts := c.options
+
+	if opts.quorumQueue {
+		errs = append(errs, fmt.Errorf("a queue cannot be both quorum and stream"))
+	}
+	if opts.deadLetterQueue != "" {
+		errs = append(errs, fmt.Errorf("dead letter is not supported by streams"))
+	}
+	if opts.retries > 0 || len(opts.retryDelays) > 0 || opts.retryRepublish {
+		errs = append(errs, fmt.Errorf("retries are not supported by streams"))
+	}
+	if opts.exclusive || opts.singleActive {
+		errs = append(errs, fmt.Errorf("exclusive and single active consumers are not supported by streams"))
+	}
+	if opts.prefetchCount <= 0 {
+		errs = append(errs, fmt.Errorf("streams require a prefetch count greater than 0"))
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateStreamResult returns a permanent error when the handler of a stream event asks to requeue
+// or defer it, or to retry it later, as the copy republished would be appended to the stream again.
+// Unlike the options, those are only known once the handler returns.
+func (c *Consumer) validateStreamResult(err error) error {
+	if !c.options.streamQueue || err == nil {
+		return nil
+	}
+
+	var disposition *DispositionError
+	if errors.As(err, &disposition) && disposition.Disposition != DispositionReject {
+		return Permanent(fmt.Errorf("disposition %s is not supported by streams", disposition.Disposition))
+	}
+
+	var retryable *RetryableError
+	if errors.As(err, &retryable) && retryable.After > 0 {
+		return Permanent(fmt.Errorf("retries are not supported by streams: %s", retryable.Err))
+	}
+
+	return nil
+}
+
+// consumeArgs returns the arguments used to start consuming. For streams, the offset after
+// the last one handled is used when resuming or reconnecting, then the one after the stored
+// offset, otherwise the one specified on the options.
+func (c *Consumer) consumeArgs() (amqp.Table, error) {
+	if !c.options.streamQueue {
+		return nil, nil
+	}
+
+	if offset := c.lastOffset.Load(); offset != nil {
+		return amqp.Table{"x-stream-offset": *offset + 1}, nil
+	}
+
+	if c.options.offsetStore != nil {
+		offset, found, err := c.options.offsetStore.Load(c.options.name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load stream offset: %w", err)
+		}
+		if found {
+			return amqp.Table{"x-stream-offset": offset + 1}, nil
+		}
+	}
+
+	if c.options.streamOffset.value == nil {
+		return nil, nil
+	}
+
+	return amqp.Table{"x-stream-offset": c.options.streamOffset.value}, nil
+}
+
+// offsetTracker keeps the offsets delivered on a channel in order, so the offset saved only
+// advances past the events that were all settled. Events handled in parallel or in batches can
+// finish out of order, and saving a greater offset first would skip the ones still being handled.
+type offsetTracker struct {
+	mu      sync.Mutex
+	pending []int64
+	settled map[int64]struct{}
+}
+
+// newOffsetTracker returns a tracker for streams, or nil as other queues do not have offsets.
+func (c *Consumer) newOffsetTracker() *offsetTracker {
+	if !c.options.streamQueue {
+		return nil
+	}
+	return &offsetTracker{settled: make(map[int64]struct{})}
+}
+
+// delivered registers the offset of the delivery. It is called in the order of the deliveries.
+func (t *offsetTracker) delivered(delivery amqp.Delivery) {
+	offset, ok := headerInt(delivery.Headers, "x-stream-offset")
+	if t == nil || !ok {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pending = append(t.pending, offset)
+}
+
+// settle marks the offset of the delivery as settled, successfully or not, as the stream does
+// not deliver it again. It returns the greatest offset which previous offsets are all settled, if it advanced.
+func (t *offsetTracker) settle(delivery amqp.Delivery) (int64, bool) {
+	offset, ok := headerInt(delivery.Headers, "x-stream-offset")
+	if t == nil || !ok {
+		return 0, false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.settled[offset] = struct{}{}
+	advanced := false
+	var committed int64
+	for len(t.pending) > 0 {
+		if _, ok := t.settled[t.pending[0]]; !ok {
+			break
+		}
+		committed = t.pending[0]
+		advanced = true
+		delete(t.settled, committed)
+		t.pending = t.pending[1:]
+	}
+	return committed, advanced
+}
+
+// offsetSettled keeps the offset up to which every event was settled, and stores it if an
+// OffsetStore is supplied, so those events are not read again when the consumer reconnects or restarts.
+func (c *Consumer) offsetSettled(offsets *offsetTracker, delivery amqp.Delivery) {
+	offset, ok := offsets.settle(delivery)
+	if !ok {
+		return
+	}
+
+	// A previous channel can still settle its events, so only a greater offset is kept
+	for {
+		last := c.lastOffset.Load()
+		if last != nil && *last >= offset {
+			break
+		}
+		if c.lastOffset.CompareAndSwap(last, &offset) {
+			break
+		}
+	}
+
+	if c.options.offsetStore == nil {
+		return
+	}
+
+	if err := c.options.offsetStore.Save(c.options.name, offset); err != nil {
+		notifyOffsetSaveFailed(c.options.notificationCh, c.queueName, err)
+	}
+}
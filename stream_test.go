@@ -0,0 +1,121 @@
+package bunnify
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+func TestConsumeArgs(t *testing.T) {
+	t.Run("When no event was handled the offset of the options is used", func(t *testing.T) {
+		// Setup
+		consumer := Consumer{&consumerCore{options: consumerOption{streamQueue: true, streamOffset: StreamOffsetFirst}}}
+
+		// Exercise
+		args, err := consumer.consumeArgs()
+
+		// Assert
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(args, amqp.Table{"x-stream-offset": "first"}) {
+			t.Fatalf("unexpected arguments %v", args)
+		}
+	})
+
+	t.Run("When events were handled it resumes after the greatest offset", func(t *testing.T) {
+		// Setup
+		consumer := Consumer{&consumerCore{options: consumerOption{streamQueue: true, streamOffset: StreamOffsetFirst}}}
+		offsets := consumer.newOffsetTracker()
+		for _, offset := range []int64{9, 10} {
+			offsets.delivered(streamDelivery(offset))
+		}
+		for _, offset := range []int64{10, 9} {
+			consumer.offsetSettled(offsets, streamDelivery(offset))
+		}
+
+		// Exercise
+		args, err := consumer.consumeArgs()
+
+		// Assert
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(args, amqp.Table{"x-stream-offset": int64(11)}) {
+			t.Fatalf("unexpected arguments %v", args)
+		}
+	})
+}
+
+func TestOffsetTracker(t *testing.T) {
+	t.Run("When events settle out of order the offset only advances past the settled ones", func(t *testing.T) {
+		// Setup
+		consumer := Consumer{&consumerCore{options: consumerOption{streamQueue: true}}}
+		offsets := consumer.newOffsetTracker()
+		for _, offset := range []int64{9, 10, 11} {
+			offsets.delivered(streamDelivery(offset))
+		}
+
+		// Exercise & Assert
+		if _, ok := offsets.settle(streamDelivery(10)); ok {
+			t.Fatal("expected the offset not to advance while 9 is not settled")
+		}
+		if offset, ok := offsets.settle(streamDelivery(9)); !ok || offset != 10 {
+			t.Fatalf("expected the offset to advance to 10, got %d", offset)
+		}
+		if offset, ok := offsets.settle(streamDelivery(11)); !ok || offset != 11 {
+			t.Fatalf("expected the offset to advance to 11, got %d", offset)
+		}
+	})
+
+	t.Run("When the queue is not a stream offsets are not tracked", func(t *testing.T) {
+		// Setup
+		consumer := Consumer{&consumerCore{}}
+		offsets := consumer.newOffsetTracker()
+
+		// Exercise
+		offsets.delivered(streamDelivery(1))
+		_, ok := offsets.settle(streamDelivery(1))
+
+		// Assert
+		if ok {
+			t.Fatal("expected no offset")
+		}
+	})
+}
+
+func streamDelivery(offset int64) amqp.Delivery {
+	return amqp.Delivery{Headers: amqp.Table{"x-stream-offset": offset}}
+}
+
+func TestValidateStreamResult(t *testing.T) {
+	consumer := Consumer{&consumerCore{options: consumerOption{streamQueue: true}}}
+
+	tests := map[string]struct {
+		err      error
+		rejected bool
+	}{
+		"Requeue":               {err: Requeue(), rejected: true},
+		"Defer":                 {err: Defer(time.Second), rejected: true},
+		"Retryable after delay": {err: Retryable(errors.New("unavailable"), time.Second), rejected: true},
+		"Reject":                {err: Reject()},
+		"Failure":               {err: errors.New("failure")},
+		"Success":               {},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Exercise
+			err := consumer.validateStreamResult(tt.err)
+
+			// Assert
+			var permanent *PermanentError
+			if tt.rejected != errors.As(err, &permanent) {
+				t.Fatalf("expected rejected %t, got %v", tt.rejected, err)
+			}
+		})
+	}
+}
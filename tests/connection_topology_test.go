@@ -0,0 +1,146 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pmorelli92/bunnify"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.uber.org/goleak"
+)
+
+func TestConnectionTopology(t *testing.T) {
+	t.Run("Topology is declared on connect", func(t *testing.T) {
+		// Setup
+		queueName := uuid.NewString()
+		exchangeName := uuid.NewString()
+		upstreamExchangeName := uuid.NewString()
+		alternateExchangeName := uuid.NewString()
+		unroutedQueueName := uuid.NewString()
+		routingKey := "order.orderCreated"
+
+		type orderCreated struct {
+			ID string `json:"id"`
+		}
+
+		topology := bunnify.Topology{
+			Exchanges: []bunnify.ExchangeDefinition{
+				{Name: alternateExchangeName, Kind: bunnify.ExchangeKindFanout, Durable: true},
+				{Name: upstreamExchangeName, Kind: bunnify.ExchangeKindTopic, Durable: true},
+				{Name: exchangeName, Kind: bunnify.ExchangeKindTopic, Durable: true, AlternateExchange: alternateExchangeName},
+			},
+			Queues: []bunnify.QueueDefinition{
+				{Name: queueName, Durable: true},
+				{Name: unroutedQueueName, Durable: true},
+			},
+			ExchangeBindings: []bunnify.ExchangeBinding{
+				{Destination: exchangeName, Source: upstreamExchangeName, RoutingKey: "#"},
+			},
+			QueueBindings: []bunnify.QueueBinding{
+				{Queue: queueName, Exchange: exchangeName, RoutingKey: "order.*"},
+				{Queue: unroutedQueueName, Exchange: alternateExchangeName},
+			},
+		}
+
+		var consumedEvent bunnify.ConsumableEvent[orderCreated]
+		eventHandler := func(ctx context.Context, event bunnify.ConsumableEvent[orderCreated]) error {
+			consumedEvent = event
+			return nil
+		}
+
+		// Exercise
+		connection := bunnify.NewConnection(bunnify.WithTopology(topology))
+		if err := connection.Start(); err != nil {
+			t.Fatal(err)
+		}
+
+		// The queue is declared by the topology, the consumer only consumes from it
+		consumer := connection.NewConsumer(
+			queueName,
+			bunnify.WithHandler(routingKey, eventHandler))
+
+		if err := consumer.Consume(); err != nil {
+			t.Fatal(err)
+		}
+
+		publisher := connection.NewPublisher()
+
+		publishedEvent := bunnify.NewPublishableEvent(orderCreated{ID: uuid.NewString()})
+		if err := publisher.Publish(context.TODO(), upstreamExchangeName, routingKey, publishedEvent); err != nil {
+			t.Fatal(err)
+		}
+
+		unroutedEvent := bunnify.NewPublishableEvent(orderCreated{ID: uuid.NewString()})
+		if err := publisher.Publish(context.TODO(), exchangeName, "payment.paymentCreated", unroutedEvent); err != nil {
+			t.Fatal(err)
+		}
+
+		time.Sleep(50 * time.Millisecond)
+
+		amqpConnection, err := amqp.Dial("amqp://localhost:5672")
+		if err != nil {
+			t.Fatal(err)
+		}
+		channel, err := amqpConnection.Channel()
+		if err != nil {
+			t.Fatal(err)
+		}
+		unrouted, err := channel.QueueDeclarePassive(unroutedQueueName, true, false, false, false, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := amqpConnection.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if err := connection.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		// Assert
+		if publishedEvent.ID != consumedEvent.ID {
+			t.Fatalf("expected event ID %s, got %s", publishedEvent.ID, consumedEvent.ID)
+		}
+		if unrouted.Messages != 1 {
+			t.Fatalf("expected 1 unrouted event, got %d", unrouted.Messages)
+		}
+
+		goleak.VerifyNone(t)
+	})
+
+	t.Run("Failures are reported per entity", func(t *testing.T) {
+		// Setup
+		queueName := uuid.NewString()
+
+		topology := bunnify.Topology{
+			Queues: []bunnify.QueueDefinition{
+				{Name: queueName, Durable: true},
+			},
+			QueueBindings: []bunnify.QueueBinding{
+				{Queue: queueName, Exchange: uuid.NewString(), RoutingKey: "#"},
+			},
+		}
+
+		// Exercise
+		connection := bunnify.NewConnection(bunnify.WithTopology(topology))
+		err := connection.Start()
+
+		if closeErr := connection.Close(); closeErr != nil {
+			t.Fatal(closeErr)
+		}
+
+		// Assert
+		var topologyErr *bunnify.TopologyError
+		if !errors.As(err, &topologyErr) {
+			t.Fatalf("expected topology error, got %v", err)
+		}
+		if len(topologyErr.Errors) != 1 {
+			t.Fatalf("expected 1 failed entity, got %v", topologyErr.Errors)
+		}
+
+		goleak.VerifyNone(t)
+	})
+}
@@ -0,0 +1,145 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pmorelli92/bunnify"
+	"go.uber.org/goleak"
+)
+
+func TestConsumerBatchHandler(t *testing.T) {
+	type orderCreated struct {
+		ID string `json:"id"`
+	}
+
+	t.Run("Batches are flushed by size and by max wait", func(t *testing.T) {
+		// Setup
+		queueName := uuid.NewString()
+		exchangeName := uuid.NewString()
+		routingKey := uuid.NewString()
+
+		mu := sync.Mutex{}
+		var batchSizes []int
+		batchHandler := func(ctx context.Context, events []bunnify.ConsumableEvent[orderCreated]) error {
+			mu.Lock()
+			defer mu.Unlock()
+			batchSizes = append(batchSizes, len(events))
+			return nil
+		}
+
+		// Exercise
+		connection := bunnify.NewConnection()
+		if err := connection.Start(); err != nil {
+			t.Fatal(err)
+		}
+
+		consumer := connection.NewConsumer(
+			queueName,
+			bunnify.WithBindingToExchange(exchangeName),
+			bunnify.WithBatchHandler(routingKey, 5, 100*time.Millisecond, batchHandler))
+
+		if err := consumer.Consume(); err != nil {
+			t.Fatal(err)
+		}
+
+		publisher := connection.NewPublisher()
+		for i := 0; i < 7; i++ {
+			err := publisher.Publish(context.TODO(), exchangeName, routingKey, bunnify.NewPublishableEvent(orderCreated{
+				ID: uuid.NewString(),
+			}))
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		time.Sleep(300 * time.Millisecond)
+
+		if err := connection.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		// Assert
+		mu.Lock()
+		defer mu.Unlock()
+		if len(batchSizes) != 2 || batchSizes[0] != 5 || batchSizes[1] != 2 {
+			t.Fatalf("expected batches of 5 and 2, got %v", batchSizes)
+		}
+	})
+
+	t.Run("Partial failures go to dead letter", func(t *testing.T) {
+		// Setup
+		queueName := uuid.NewString()
+		deadLetterQueueName := uuid.NewString()
+		exchangeName := uuid.NewString()
+		routingKey := uuid.NewString()
+
+		batchHandler := func(ctx context.Context, events []bunnify.ConsumableEvent[orderCreated]) error {
+			return &bunnify.BatchError{Errors: map[int]error{
+				1: fmt.Errorf("error, this event will go to dead-letter"),
+			}}
+		}
+
+		mu := sync.Mutex{}
+		var deadEvents []string
+		deadEventHandler := func(ctx context.Context, event bunnify.ConsumableEvent[orderCreated]) error {
+			mu.Lock()
+			defer mu.Unlock()
+			deadEvents = append(deadEvents, event.ID)
+			return nil
+		}
+
+		// Exercise
+		connection := bunnify.NewConnection()
+		if err := connection.Start(); err != nil {
+			t.Fatal(err)
+		}
+
+		consumer := connection.NewConsumer(
+			queueName,
+			bunnify.WithBindingToExchange(exchangeName),
+			bunnify.WithBatchHandler(routingKey, 3, time.Second, batchHandler),
+			bunnify.WithDeadLetterQueue(deadLetterQueueName))
+
+		if err := consumer.Consume(); err != nil {
+			t.Fatal(err)
+		}
+
+		deadLetterConsumer := connection.NewConsumer(
+			deadLetterQueueName,
+			bunnify.WithHandler(routingKey, deadEventHandler))
+
+		if err := deadLetterConsumer.Consume(); err != nil {
+			t.Fatal(err)
+		}
+
+		publisher := connection.NewPublisher()
+		publishedEvents := make([]bunnify.PublishableEvent, 3)
+		for i := range publishedEvents {
+			publishedEvents[i] = bunnify.NewPublishableEvent(orderCreated{ID: uuid.NewString()})
+			err := publisher.Publish(context.TODO(), exchangeName, routingKey, publishedEvents[i])
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		time.Sleep(100 * time.Millisecond)
+
+		if err := connection.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		// Assert
+		mu.Lock()
+		defer mu.Unlock()
+		if len(deadEvents) != 1 || deadEvents[0] != publishedEvents[1].ID {
+			t.Fatalf("expected only event %s on dead letter, got %v", publishedEvents[1].ID, deadEvents)
+		}
+	})
+
+	goleak.VerifyNone(t)
+}
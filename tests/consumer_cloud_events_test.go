@@ -0,0 +1,84 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pmorelli92/bunnify"
+	"go.uber.org/goleak"
+)
+
+func TestConsumerCloudEvents(t *testing.T) {
+	type orderCreated struct {
+		ID string `json:"id"`
+	}
+
+	for _, format := range []bunnify.EventFormat{
+		bunnify.EventFormatCloudEventsBinary,
+		bunnify.EventFormatCloudEventsStructured,
+	} {
+		t.Run(string(format), func(t *testing.T) {
+			// Setup
+			queueName := uuid.NewString()
+			exchangeName := uuid.NewString()
+			routingKey := "order.orderCreated"
+
+			publishedEvent := bunnify.NewPublishableEvent(
+				orderCreated{ID: uuid.NewString()},
+				bunnify.WithSource("/orders"),
+				bunnify.WithSubject("order"))
+
+			var consumedEvent bunnify.ConsumableEvent[orderCreated]
+			eventHandler := func(ctx context.Context, event bunnify.ConsumableEvent[orderCreated]) error {
+				consumedEvent = event
+				return nil
+			}
+
+			// Exercise
+			connection := bunnify.NewConnection(bunnify.WithEventFormat(format))
+			if err := connection.Start(); err != nil {
+				t.Fatal(err)
+			}
+
+			consumer := connection.NewConsumer(
+				queueName,
+				bunnify.WithBindingToExchange(exchangeName),
+				bunnify.WithHandler(routingKey, eventHandler))
+
+			if err := consumer.Consume(); err != nil {
+				t.Fatal(err)
+			}
+
+			publisher := connection.NewPublisher()
+
+			err := publisher.Publish(context.TODO(), exchangeName, routingKey, publishedEvent)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			time.Sleep(50 * time.Millisecond)
+
+			if err := connection.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			// Assert
+			if publishedEvent.ID != consumedEvent.ID {
+				t.Fatalf("expected event ID %s, got %s", publishedEvent.ID, consumedEvent.ID)
+			}
+			if publishedEvent.CorrelationID != consumedEvent.CorrelationID {
+				t.Fatalf("expected correlation ID %s, got %s", publishedEvent.CorrelationID, consumedEvent.CorrelationID)
+			}
+			if consumedEvent.Source != "/orders" || consumedEvent.Subject != "order" || consumedEvent.Type != routingKey {
+				t.Fatalf("unexpected attributes %+v", consumedEvent.Metadata)
+			}
+			if publishedEvent.Payload != consumedEvent.Payload {
+				t.Fatalf("expected payload %v, got %v", publishedEvent.Payload, consumedEvent.Payload)
+			}
+
+			goleak.VerifyNone(t)
+		})
+	}
+}
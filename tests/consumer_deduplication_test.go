@@ -0,0 +1,74 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pmorelli92/bunnify"
+	"go.uber.org/goleak"
+)
+
+func TestConsumerDeduplication(t *testing.T) {
+	// Setup
+	queueName := uuid.NewString()
+	exchangeName := uuid.NewString()
+	routingKey := uuid.NewString()
+
+	type orderCreated struct {
+		ID string `json:"id"`
+	}
+
+	processed := map[string]int{}
+	eventHandler := func(ctx context.Context, event bunnify.ConsumableEvent[orderCreated]) error {
+		processed[event.ID]++
+		if event.Payload.ID == "fails-once" && processed[event.ID] == 1 {
+			return fmt.Errorf("error, the event is not marked as processed")
+		}
+		return nil
+	}
+
+	// Exercise
+	connection := bunnify.NewConnection()
+	if err := connection.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	consumer := connection.NewConsumer(
+		queueName,
+		bunnify.WithDeduplication(bunnify.NewMemoryDedupStore(time.Minute, 100)),
+		bunnify.WithBindingToExchange(exchangeName),
+		bunnify.WithHandler(routingKey, eventHandler))
+
+	if err := consumer.Consume(); err != nil {
+		t.Fatal(err)
+	}
+
+	publisher := connection.NewPublisher()
+
+	succeeds := bunnify.NewPublishableEvent(orderCreated{ID: "succeeds"})
+	failsOnce := bunnify.NewPublishableEvent(orderCreated{ID: "fails-once"})
+	for _, event := range []bunnify.PublishableEvent{succeeds, succeeds, failsOnce, failsOnce, failsOnce} {
+		if err := publisher.Publish(context.TODO(), exchangeName, routingKey, event); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	if err := connection.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Assert
+	if processed[succeeds.ID] != 1 {
+		t.Fatalf("expected event to be processed once, got %d", processed[succeeds.ID])
+	}
+	if processed[failsOnce.ID] != 2 {
+		t.Fatalf("expected failed event to be processed again, got %d", processed[failsOnce.ID])
+	}
+
+	goleak.VerifyNone(t)
+}
@@ -0,0 +1,269 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pmorelli92/bunnify"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.uber.org/goleak"
+)
+
+func TestConsumerDispositions(t *testing.T) {
+	type orderCreated struct {
+		ID string `json:"id"`
+	}
+
+	t.Run("Requeue does not count as retry", func(t *testing.T) {
+		// Setup
+		queueName := uuid.NewString()
+		exchangeName := uuid.NewString()
+		routingKey := uuid.NewString()
+
+		actualProcessing := 0
+		eventHandler := func(ctx context.Context, event bunnify.ConsumableEvent[orderCreated]) error {
+			actualProcessing++
+			if actualProcessing < 3 {
+				return bunnify.Requeue()
+			}
+			return nil
+		}
+
+		// Exercise
+		connection := bunnify.NewConnection()
+		if err := connection.Start(); err != nil {
+			t.Fatal(err)
+		}
+
+		// No retries are allowed, yet the event can be requeued
+		consumer := connection.NewConsumer(
+			queueName,
+			bunnify.WithBindingToExchange(exchangeName),
+			bunnify.WithHandler(routingKey, eventHandler))
+
+		if err := consumer.Consume(); err != nil {
+			t.Fatal(err)
+		}
+
+		publisher := connection.NewPublisher()
+
+		err := publisher.Publish(context.TODO(), exchangeName, routingKey, bunnify.NewPublishableEvent(orderCreated{
+			ID: uuid.NewString(),
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		time.Sleep(100 * time.Millisecond)
+
+		if err := connection.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		// Assert
+		if actualProcessing != 3 {
+			t.Fatalf("expected processing 3, got %d", actualProcessing)
+		}
+	})
+
+	t.Run("Reject sends to dead letter without retrying", func(t *testing.T) {
+		// Setup
+		queueName := uuid.NewString()
+		deadLetterQueueName := uuid.NewString()
+		exchangeName := uuid.NewString()
+		routingKey := uuid.NewString()
+
+		actualProcessing := 0
+		eventHandler := func(ctx context.Context, event bunnify.ConsumableEvent[orderCreated]) error {
+			actualProcessing++
+			return bunnify.Reject()
+		}
+
+		var deadEvent bunnify.ConsumableEvent[orderCreated]
+		deadEventHandler := func(ctx context.Context, event bunnify.ConsumableEvent[orderCreated]) error {
+			deadEvent = event
+			return nil
+		}
+
+		// Exercise
+		connection := bunnify.NewConnection()
+		if err := connection.Start(); err != nil {
+			t.Fatal(err)
+		}
+
+		consumer := connection.NewConsumer(
+			queueName,
+			bunnify.WithRetries(3),
+			bunnify.WithRetriesByRepublish(),
+			bunnify.WithBindingToExchange(exchangeName),
+			bunnify.WithHandler(routingKey, eventHandler),
+			bunnify.WithDeadLetterQueue(deadLetterQueueName))
+
+		if err := consumer.Consume(); err != nil {
+			t.Fatal(err)
+		}
+
+		deadLetterConsumer := connection.NewConsumer(
+			deadLetterQueueName,
+			bunnify.WithHandler(routingKey, deadEventHandler))
+
+		if err := deadLetterConsumer.Consume(); err != nil {
+			t.Fatal(err)
+		}
+
+		publisher := connection.NewPublisher()
+
+		publishedEvent := bunnify.NewPublishableEvent(orderCreated{ID: uuid.NewString()})
+		err := publisher.Publish(context.TODO(), exchangeName, routingKey, publishedEvent)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		time.Sleep(50 * time.Millisecond)
+
+		if err := connection.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		// Assert
+		if actualProcessing != 1 {
+			t.Fatalf("expected processing 1, got %d", actualProcessing)
+		}
+		if publishedEvent.ID != deadEvent.ID {
+			t.Fatalf("expected event ID %s, got %s", publishedEvent.ID, deadEvent.ID)
+		}
+	})
+
+	t.Run("Defer processes the event again after the delay", func(t *testing.T) {
+		// Setup
+		queueName := uuid.NewString()
+		exchangeName := uuid.NewString()
+		routingKey := uuid.NewString()
+		deferFor := 200 * time.Millisecond
+
+		var processedAt []time.Time
+		eventHandler := func(ctx context.Context, event bunnify.ConsumableEvent[orderCreated]) error {
+			processedAt = append(processedAt, time.Now())
+			if len(processedAt) == 1 {
+				return bunnify.Defer(deferFor)
+			}
+			return nil
+		}
+
+		// Exercise
+		connection := bunnify.NewConnection()
+		if err := connection.Start(); err != nil {
+			t.Fatal(err)
+		}
+
+		consumer := connection.NewConsumer(
+			queueName,
+			bunnify.WithBindingToExchange(exchangeName),
+			bunnify.WithHandler(routingKey, eventHandler))
+
+		if err := consumer.Consume(); err != nil {
+			t.Fatal(err)
+		}
+
+		publisher := connection.NewPublisher()
+
+		err := publisher.Publish(context.TODO(), exchangeName, routingKey, bunnify.NewPublishableEvent(orderCreated{
+			ID: uuid.NewString(),
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		time.Sleep(500 * time.Millisecond)
+
+		if err := connection.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		// Assert
+		if len(processedAt) != 2 {
+			t.Fatalf("expected processing 2, got %d", len(processedAt))
+		}
+		if waited := processedAt[1].Sub(processedAt[0]); waited < deferFor {
+			t.Fatalf("expected event to be deferred at least %s, waited %s", deferFor, waited)
+		}
+	})
+
+	goleak.VerifyNone(t)
+}
+
+func TestConsumerDeclaresDeferredQueueOnFirstDeferral(t *testing.T) {
+	// Setup
+	queueName := uuid.NewString()
+	deferredQueueName := fmt.Sprintf("%s-deferred", queueName)
+
+	defaultHandler := func(ctx context.Context, event bunnify.ConsumableEvent[json.RawMessage]) error {
+		return bunnify.Defer(time.Hour)
+	}
+
+	// Exercise
+	connection := bunnify.NewConnection()
+	if err := connection.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	consumer := connection.NewConsumer(
+		queueName,
+		bunnify.WithQuorumQueue(),
+		bunnify.WithDefaultHandler(defaultHandler))
+
+	if err := consumer.Consume(); err != nil {
+		t.Fatal(err)
+	}
+
+	amqpConnection, err := amqp.Dial("amqp://localhost:5672")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Not declared with the other queues, as no event was deferred yet
+	channel, err := amqpConnection.Channel()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, beforeErr := channel.QueueDeclarePassive(deferredQueueName, true, false, false, false, nil)
+
+	// The failed passive declaration closed the channel
+	channel, err = amqpConnection.Channel()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publisher := connection.NewPublisher()
+	if err := publisher.Publish(context.TODO(), "", queueName, bunnify.NewPublishableEvent(struct{}{})); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	deferredQueue, afterErr := channel.QueueDeclarePassive(deferredQueueName, true, false, false, false, nil)
+
+	if err := amqpConnection.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := connection.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Assert
+	if beforeErr == nil {
+		t.Fatal("expected the deferred queue not to exist before deferring an event")
+	}
+	if afterErr != nil {
+		t.Fatalf("expected the deferred queue to exist, got %s", afterErr)
+	}
+	if deferredQueue.Messages != 1 {
+		t.Fatalf("expected the event in the deferred queue, got %d messages", deferredQueue.Messages)
+	}
+
+	goleak.VerifyNone(t)
+}
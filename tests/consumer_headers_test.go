@@ -0,0 +1,107 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pmorelli92/bunnify"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.uber.org/goleak"
+)
+
+func TestConsumerHeadersExchange(t *testing.T) {
+	// Setup
+	queueName := uuid.NewString()
+	exchangeName := uuid.NewString()
+
+	type orderEvent struct {
+		ID string `json:"id"`
+	}
+
+	var euEvents, acmeEvents, defaultEvents int
+	euHandler := func(ctx context.Context, event bunnify.ConsumableEvent[orderEvent]) error {
+		euEvents++
+		return nil
+	}
+	acmeHandler := func(ctx context.Context, event bunnify.ConsumableEvent[orderEvent]) error {
+		acmeEvents++
+		return nil
+	}
+	defaultHandler := func(ctx context.Context, event bunnify.ConsumableEvent[json.RawMessage]) error {
+		defaultEvents++
+		return nil
+	}
+
+	// Exercise
+	connection := bunnify.NewConnection()
+	if err := connection.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	consumer := connection.NewConsumer(
+		queueName,
+		bunnify.WithBindingToExchange(exchangeName),
+		bunnify.WithExchangeKind(bunnify.ExchangeKindHeaders),
+		bunnify.WithHeaderHandler(bunnify.HeaderMatchAll, map[string]any{"type": "order", "region": "eu"}, euHandler),
+		bunnify.WithHeaderHandler(bunnify.HeaderMatchAny, map[string]any{"tenant": "acme"}, acmeHandler),
+		bunnify.WithDefaultHandler(defaultHandler))
+
+	if err := consumer.Consume(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The bunnify publisher does not allow custom headers, so the raw channel is used
+	amqpConnection, err := amqp.Dial("amqp://localhost:5672")
+	if err != nil {
+		t.Fatal(err)
+	}
+	channel, err := amqpConnection.Channel()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publish := func(headers amqp.Table) {
+		body, err := json.Marshal(bunnify.NewPublishableEvent(orderEvent{ID: uuid.NewString()}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = channel.PublishWithContext(context.TODO(), exchangeName, "", false, false, amqp.Publishing{
+			Headers: headers,
+			Body:    body,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	publish(amqp.Table{"type": "order", "region": "eu"})
+	publish(amqp.Table{"type": "order", "region": "us"})
+	publish(amqp.Table{"tenant": "acme", "region": "us"})
+
+	time.Sleep(50 * time.Millisecond)
+
+	if err := amqpConnection.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := connection.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Assert
+	if euEvents != 1 {
+		t.Fatalf("expected 1 eu event, got %d", euEvents)
+	}
+	if acmeEvents != 1 {
+		t.Fatalf("expected 1 acme event, got %d", acmeEvents)
+	}
+
+	// The us order is not bound to the queue, so it never reaches the default handler
+	if defaultEvents != 0 {
+		t.Fatalf("expected 0 default events, got %d", defaultEvents)
+	}
+
+	goleak.VerifyNone(t)
+}
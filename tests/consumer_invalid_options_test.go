@@ -1,6 +1,8 @@
 package tests
 
 import (
+	"context"
+	"encoding/json"
 	"testing"
 
 	"github.com/pmorelli92/bunnify"
@@ -44,3 +46,60 @@ func TestConsumerShouldReturnErrorWhenNoHandlersSpecified(t *testing.T) {
 
 	goleak.VerifyNone(t)
 }
+
+func TestConsumerShouldReturnErrorWhenInvalidQueueArguments(t *testing.T) {
+	// Setup
+	connection := bunnify.NewConnection()
+	if err := connection.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Exercise
+	consumer := connection.NewConsumer(
+		"queueName",
+		bunnify.WithQuorumQueue(),
+		bunnify.WithMaxPriority(10),
+		bunnify.WithDefaultHandler(func(ctx context.Context, event bunnify.ConsumableEvent[json.RawMessage]) error {
+			return nil
+		}))
+	err := consumer.Consume()
+
+	// Assert
+	if err == nil {
+		t.Fatal("expected error as quorum queues do not support max priority")
+	}
+
+	if err := connection.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	goleak.VerifyNone(t)
+}
+
+func TestConsumerShouldReturnErrorWhenRetriesByRepublishWithoutRetries(t *testing.T) {
+	// Setup
+	connection := bunnify.NewConnection()
+	if err := connection.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Exercise
+	consumer := connection.NewConsumer(
+		"queueName",
+		bunnify.WithRetriesByRepublish(),
+		bunnify.WithDefaultHandler(func(ctx context.Context, event bunnify.ConsumableEvent[json.RawMessage]) error {
+			return nil
+		}))
+	err := consumer.Consume()
+
+	// Assert
+	if err == nil {
+		t.Fatal("expected error as retries by republish require retries")
+	}
+
+	if err := connection.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	goleak.VerifyNone(t)
+}
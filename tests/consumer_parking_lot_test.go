@@ -0,0 +1,118 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pmorelli92/bunnify"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.uber.org/goleak"
+)
+
+func TestConsumerParkingLot(t *testing.T) {
+	// Setup
+	queueName := uuid.NewString()
+	parkingLotQueueName := uuid.NewString()
+	exchangeName := uuid.NewString()
+	routingKey := "order.orderCreated"
+
+	type orderCreated struct {
+		ID string `json:"id"`
+	}
+
+	eventHandler := func(ctx context.Context, event bunnify.ConsumableEvent[orderCreated]) error {
+		return nil
+	}
+
+	// Exercise
+	connection := bunnify.NewConnection()
+	if err := connection.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	consumer := connection.NewConsumer(
+		queueName,
+		bunnify.WithBindingToExchange(exchangeName),
+		bunnify.WithHandler(routingKey, eventHandler),
+		bunnify.WithParkingLotQueue(parkingLotQueueName))
+
+	if err := consumer.Consume(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The bunnify publisher always sends valid events, so the raw channel is used
+	amqpConnection, err := amqp.Dial("amqp://localhost:5672")
+	if err != nil {
+		t.Fatal(err)
+	}
+	channel, err := amqpConnection.Channel()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unparsableBody := []byte("not a bunnify event")
+	err = channel.PublishWithContext(context.TODO(), exchangeName, routingKey, false, false, amqp.Publishing{
+		MessageId: "unparsable",
+		Body:      unparsableBody,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Sent to the queue directly, the routing key is the queue name which has no handler
+	err = channel.PublishWithContext(context.TODO(), "", queueName, false, false, amqp.Publishing{
+		MessageId: "without-handler",
+		Body:      []byte(`{"id":"1","payload":{}}`),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	parked := map[string]amqp.Delivery{}
+	for range 2 {
+		delivery, ok, err := channel.Get(parkingLotQueueName, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			break
+		}
+		parked[delivery.MessageId] = delivery
+	}
+
+	if err := amqpConnection.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := connection.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Assert
+	unparsable, ok := parked["unparsable"]
+	if !ok {
+		t.Fatal("expected unparsable event to be parked")
+	}
+	if string(unparsable.Body) != string(unparsableBody) {
+		t.Fatalf("expected body %s, got %s", unparsableBody, unparsable.Body)
+	}
+	if unparsable.Headers["x-bunnify-parked-reason"] != "not_parsable" {
+		t.Fatalf("unexpected reason %v", unparsable.Headers["x-bunnify-parked-reason"])
+	}
+
+	withoutHandler, ok := parked["without-handler"]
+	if !ok {
+		t.Fatal("expected event without handler to be parked")
+	}
+	if withoutHandler.Headers["x-bunnify-parked-reason"] != "handler_not_found" {
+		t.Fatalf("unexpected reason %v", withoutHandler.Headers["x-bunnify-parked-reason"])
+	}
+	if withoutHandler.Headers["x-bunnify-original-routing-key"] != queueName {
+		t.Fatalf("unexpected routing key %v", withoutHandler.Headers["x-bunnify-original-routing-key"])
+	}
+
+	goleak.VerifyNone(t)
+}
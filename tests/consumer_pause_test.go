@@ -0,0 +1,83 @@
+package tests
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pmorelli92/bunnify"
+	"go.uber.org/goleak"
+)
+
+func TestConsumerPauseResume(t *testing.T) {
+	// Setup
+	queueName := uuid.NewString()
+	exchangeName := uuid.NewString()
+	routingKey := "order.orderCreated"
+
+	type orderCreated struct {
+		ID string `json:"id"`
+	}
+
+	var handled atomic.Int32
+	eventHandler := func(ctx context.Context, event bunnify.ConsumableEvent[orderCreated]) error {
+		handled.Add(1)
+		return nil
+	}
+
+	// Exercise
+	connection := bunnify.NewConnection()
+	if err := connection.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	consumer := connection.NewConsumer(
+		queueName,
+		bunnify.WithBindingToExchange(exchangeName),
+		bunnify.WithHandler(routingKey, eventHandler))
+
+	if err := consumer.ConsumeParallel(); err != nil {
+		t.Fatal(err)
+	}
+
+	publisher := connection.NewPublisher()
+	publish := func() {
+		event := bunnify.NewPublishableEvent(orderCreated{ID: uuid.NewString()})
+		if err := publisher.Publish(context.TODO(), exchangeName, routingKey, event); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	publish()
+	time.Sleep(50 * time.Millisecond)
+
+	if err := consumer.Pause(); err != nil {
+		t.Fatal(err)
+	}
+
+	publish()
+	time.Sleep(50 * time.Millisecond)
+	handledWhilePaused := handled.Load()
+
+	if err := consumer.Resume(); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	if err := connection.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Assert
+	if handledWhilePaused != 1 {
+		t.Fatalf("expected 1 event handled while paused, got %d", handledWhilePaused)
+	}
+	if handled.Load() != 2 {
+		t.Fatalf("expected 2 events handled after resume, got %d", handled.Load())
+	}
+
+	goleak.VerifyNone(t)
+}
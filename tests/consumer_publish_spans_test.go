@@ -0,0 +1,102 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pmorelli92/bunnify"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	tracesdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/goleak"
+)
+
+func TestConsumerPublisherSpans(t *testing.T) {
+	// Setup tracing
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	recorder := tracetest.NewSpanRecorder()
+	provider := tracesdk.NewTracerProvider(tracesdk.WithSpanProcessor(recorder))
+
+	// Setup amqp
+	queueName := uuid.NewString()
+	exchangeName := uuid.NewString()
+	routingKey := uuid.NewString()
+
+	connection := bunnify.NewConnection(bunnify.WithTracerProvider(provider))
+	if err := connection.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Exercise consuming
+	eventHandler := func(ctx context.Context, event bunnify.ConsumableEvent[any]) error {
+		return fmt.Errorf("event %s could not be handled", event.ID)
+	}
+
+	consumer := connection.NewConsumer(
+		queueName,
+		bunnify.WithBindingToExchange(exchangeName),
+		bunnify.WithHandler(routingKey, eventHandler))
+	if err := consumer.Consume(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Exercise publishing
+	publisher := connection.NewPublisher()
+	eventID := uuid.NewString()
+
+	err := publisher.Publish(
+		context.TODO(),
+		exchangeName,
+		routingKey,
+		bunnify.NewPublishableEvent(struct{}{}, bunnify.WithEventID(eventID)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	if err := connection.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Assert
+	var publishSpan, processSpan tracesdk.ReadOnlySpan
+	for _, span := range recorder.Ended() {
+		switch span.SpanKind() {
+		case trace.SpanKindProducer:
+			publishSpan = span
+		case trace.SpanKindConsumer:
+			processSpan = span
+		}
+	}
+
+	if publishSpan == nil || processSpan == nil {
+		t.Fatalf("expected publish and process spans, got %d spans", len(recorder.Ended()))
+	}
+	if publishSpan.Name() != fmt.Sprintf("publish %s", exchangeName) {
+		t.Fatalf("unexpected publish span name %s", publishSpan.Name())
+	}
+	if processSpan.Name() != fmt.Sprintf("process %s", queueName) {
+		t.Fatalf("unexpected process span name %s", processSpan.Name())
+	}
+	if processSpan.Parent().SpanID() != publishSpan.SpanContext().SpanID() {
+		t.Fatal("expected process span to be a child of the publish span")
+	}
+	if processSpan.Status().Code != codes.Error {
+		t.Fatalf("expected error status, got %s", processSpan.Status().Code)
+	}
+
+	for _, kv := range processSpan.Attributes() {
+		if kv.Key == "messaging.message.id" && kv.Value.AsString() != eventID {
+			t.Fatalf("expected message id %s, got %s", eventID, kv.Value.AsString())
+		}
+	}
+
+	goleak.VerifyNone(t)
+}
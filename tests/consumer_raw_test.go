@@ -0,0 +1,142 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pmorelli92/bunnify"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.uber.org/goleak"
+)
+
+func TestConsumerRawEvents(t *testing.T) {
+	type orderCreated struct {
+		ID string `json:"id"`
+	}
+
+	t.Run("Raw handler receives the payload published without envelope", func(t *testing.T) {
+		// Setup
+		queueName := uuid.NewString()
+		exchangeName := uuid.NewString()
+		routingKey := "order.orderCreated"
+
+		publishedEvent := bunnify.NewPublishableEvent(orderCreated{ID: uuid.NewString()})
+
+		var consumedEvent bunnify.ConsumableEvent[orderCreated]
+		eventHandler := func(ctx context.Context, event bunnify.ConsumableEvent[orderCreated]) error {
+			consumedEvent = event
+			return nil
+		}
+
+		// Exercise
+		connection := bunnify.NewConnection()
+		if err := connection.Start(); err != nil {
+			t.Fatal(err)
+		}
+
+		consumer := connection.NewConsumer(
+			queueName,
+			bunnify.WithBindingToExchange(exchangeName),
+			bunnify.WithRawHandler(routingKey, eventHandler))
+
+		if err := consumer.Consume(); err != nil {
+			t.Fatal(err)
+		}
+
+		publisher := connection.NewPublisher(bunnify.WithRawPayload())
+
+		err := publisher.Publish(context.TODO(), exchangeName, routingKey, publishedEvent)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		time.Sleep(50 * time.Millisecond)
+
+		if err := connection.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		// Assert
+		if publishedEvent.ID != consumedEvent.ID {
+			t.Fatalf("expected event ID %s, got %s", publishedEvent.ID, consumedEvent.ID)
+		}
+		if publishedEvent.CorrelationID != consumedEvent.CorrelationID {
+			t.Fatalf("expected correlation ID %s, got %s", publishedEvent.CorrelationID, consumedEvent.CorrelationID)
+		}
+		if publishedEvent.Payload != consumedEvent.Payload {
+			t.Fatalf("expected payload %v, got %v", publishedEvent.Payload, consumedEvent.Payload)
+		}
+
+		goleak.VerifyNone(t)
+	})
+
+	t.Run("Raw consumer receives plain json published by other systems", func(t *testing.T) {
+		// Setup
+		queueName := uuid.NewString()
+		messageID := uuid.NewString()
+
+		var consumedEvent bunnify.ConsumableEvent[[]orderCreated]
+		eventHandler := func(ctx context.Context, event bunnify.ConsumableEvent[[]orderCreated]) error {
+			consumedEvent = event
+			return nil
+		}
+
+		// Exercise
+		connection := bunnify.NewConnection()
+		if err := connection.Start(); err != nil {
+			t.Fatal(err)
+		}
+
+		consumer := connection.NewConsumer(
+			queueName,
+			bunnify.WithRawEvents(),
+			bunnify.WithHandler(queueName, eventHandler))
+
+		if err := consumer.Consume(); err != nil {
+			t.Fatal(err)
+		}
+
+		amqpConnection, err := amqp.Dial("amqp://localhost:5672")
+		if err != nil {
+			t.Fatal(err)
+		}
+		channel, err := amqpConnection.Channel()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		body, err := json.Marshal([]orderCreated{{ID: "1"}, {ID: "2"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = channel.PublishWithContext(context.TODO(), "", queueName, false, false, amqp.Publishing{
+			MessageId: messageID,
+			Body:      body,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		time.Sleep(50 * time.Millisecond)
+
+		if err := amqpConnection.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if err := connection.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		// Assert
+		if messageID != consumedEvent.ID {
+			t.Fatalf("expected event ID %s, got %s", messageID, consumedEvent.ID)
+		}
+		if len(consumedEvent.Payload) != 2 || consumedEvent.Payload[1].ID != "2" {
+			t.Fatalf("unexpected payload %v", consumedEvent.Payload)
+		}
+
+		goleak.VerifyNone(t)
+	})
+}
@@ -0,0 +1,92 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pmorelli92/bunnify"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.uber.org/goleak"
+)
+
+func TestConsumerParkNotRoutedIsDeadLettered(t *testing.T) {
+	// Setup
+	queueName := uuid.NewString()
+	parkingLotQueueName := uuid.NewString()
+	deadLetterQueueName := uuid.NewString()
+
+	eventHandler := func(ctx context.Context, event bunnify.ConsumableEvent[any]) error {
+		return nil
+	}
+
+	// Exercise
+	connection := bunnify.NewConnection()
+	if err := connection.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	consumer := connection.NewConsumer(
+		queueName,
+		bunnify.WithHandler("routing-key", eventHandler),
+		bunnify.WithParkingLotQueue(parkingLotQueueName),
+		bunnify.WithDeadLetterQueue(deadLetterQueueName))
+
+	if err := consumer.Consume(); err != nil {
+		t.Fatal(err)
+	}
+
+	amqpConnection, err := amqp.Dial("amqp://localhost:5672")
+	if err != nil {
+		t.Fatal(err)
+	}
+	channel, err := amqpConnection.Channel()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Without the parking lot queue, the copy cannot be routed and is returned by the server
+	if _, err := channel.QueueDelete(parkingLotQueueName, false, false, false); err != nil {
+		t.Fatal(err)
+	}
+
+	err = channel.PublishWithContext(context.TODO(), "", queueName, false, false, amqp.Publishing{
+		MessageId: "unparsable",
+		Body:      []byte("not a bunnify event"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	if err := connection.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	queue, err := channel.QueueDeclarePassive(queueName, true, false, false, false, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	deadLetterQueue, err := channel.QueueDeclarePassive(deadLetterQueueName, true, false, false, false, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := amqpConnection.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Assert
+	if queue.Messages != 0 {
+		t.Fatalf("expected the event not to be requeued, got %d messages", queue.Messages)
+	}
+	if deadLetterQueue.Messages != 1 {
+		t.Fatalf("expected the event on the dead letter queue, got %d messages", deadLetterQueue.Messages)
+	}
+
+	goleak.VerifyNone(t)
+}
@@ -192,3 +192,316 @@ func TestConsumerRetriesWithDeadLetterQueue(t *testing.T) {
 
 	goleak.VerifyNone(t)
 }
+
+func TestConsumerRetriesWithDelays(t *testing.T) {
+	// Setup
+	queueName := uuid.NewString()
+	deadLetterQueueName := uuid.NewString()
+	exchangeName := uuid.NewString()
+	routingKey := "order.orderCreated"
+	delays := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}
+
+	type orderCreated struct {
+		ID string `json:"id"`
+	}
+
+	publishedEvent := bunnify.NewPublishableEvent(orderCreated{
+		ID: uuid.NewString(),
+	})
+
+	var processedAt []time.Time
+	eventHandler := func(ctx context.Context, event bunnify.ConsumableEvent[orderCreated]) error {
+		processedAt = append(processedAt, time.Now())
+		return fmt.Errorf("error, this event should be retried with delay")
+	}
+
+	var deadEvent bunnify.ConsumableEvent[orderCreated]
+	deadEventHandler := func(ctx context.Context, event bunnify.ConsumableEvent[orderCreated]) error {
+		deadEvent = event
+		return nil
+	}
+
+	// Exercise
+	connection := bunnify.NewConnection()
+	if err := connection.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	consumer := connection.NewConsumer(
+		queueName,
+		bunnify.WithRetryDelays(delays...),
+		bunnify.WithBindingToExchange(exchangeName),
+		bunnify.WithHandler(routingKey, eventHandler),
+		bunnify.WithDeadLetterQueue(deadLetterQueueName))
+
+	if err := consumer.Consume(); err != nil {
+		t.Fatal(err)
+	}
+
+	deadLetterConsumer := connection.NewConsumer(
+		deadLetterQueueName,
+		bunnify.WithHandler(routingKey, deadEventHandler))
+
+	if err := deadLetterConsumer.Consume(); err != nil {
+		t.Fatal(err)
+	}
+
+	publisher := connection.NewPublisher()
+
+	err := publisher.Publish(context.TODO(), exchangeName, routingKey, publishedEvent)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(500 * time.Millisecond)
+
+	if err := connection.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Assert
+	expectedProcessing := len(delays) + 1
+	if expectedProcessing != len(processedAt) {
+		t.Fatalf("expected processing %d, got %d", expectedProcessing, len(processedAt))
+	}
+
+	for i, delay := range delays {
+		if waited := processedAt[i+1].Sub(processedAt[i]); waited < delay {
+			t.Fatalf("expected retry %d to wait at least %s, waited %s", i+1, delay, waited)
+		}
+	}
+
+	if publishedEvent.ID != deadEvent.ID {
+		t.Fatalf("expected event ID %s, got %s", publishedEvent.ID, deadEvent.ID)
+	}
+	if routingKey != deadEvent.DeliveryInfo.RoutingKey {
+		t.Fatalf("expected routing key %s, got %s", routingKey, deadEvent.DeliveryInfo.RoutingKey)
+	}
+
+	goleak.VerifyNone(t)
+}
+
+func TestConsumerRetriesByRepublishOnClassicQueue(t *testing.T) {
+	// Setup
+	queueName := uuid.NewString()
+	deadLetterQueueName := uuid.NewString()
+	exchangeName := uuid.NewString()
+	routingKey := "order.orderCreated"
+	expectedRetries := 2
+
+	type orderCreated struct {
+		ID string `json:"id"`
+	}
+
+	publishedEvent := bunnify.NewPublishableEvent(orderCreated{
+		ID: uuid.NewString(),
+	})
+
+	actualProcessing := 0
+	eventHandler := func(ctx context.Context, event bunnify.ConsumableEvent[orderCreated]) error {
+		actualProcessing++
+		return fmt.Errorf("error, this event should be retried")
+	}
+
+	var deadEvent bunnify.ConsumableEvent[orderCreated]
+	deadEventHandler := func(ctx context.Context, event bunnify.ConsumableEvent[orderCreated]) error {
+		deadEvent = event
+		return nil
+	}
+
+	// Exercise
+	connection := bunnify.NewConnection()
+	if err := connection.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	consumer := connection.NewConsumer(
+		queueName,
+		bunnify.WithRetries(expectedRetries),
+		bunnify.WithRetriesByRepublish(),
+		bunnify.WithBindingToExchange(exchangeName),
+		bunnify.WithHandler(routingKey, eventHandler),
+		bunnify.WithDeadLetterQueue(deadLetterQueueName))
+
+	if err := consumer.Consume(); err != nil {
+		t.Fatal(err)
+	}
+
+	deadLetterConsumer := connection.NewConsumer(
+		deadLetterQueueName,
+		bunnify.WithHandler(routingKey, deadEventHandler))
+
+	if err := deadLetterConsumer.Consume(); err != nil {
+		t.Fatal(err)
+	}
+
+	publisher := connection.NewPublisher()
+
+	err := publisher.Publish(context.TODO(), exchangeName, routingKey, publishedEvent)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	if err := connection.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Assert
+	expectedProcessing := expectedRetries + 1
+	if expectedProcessing != actualProcessing {
+		t.Fatalf("expected processing %d, got %d", expectedProcessing, actualProcessing)
+	}
+
+	if publishedEvent.ID != deadEvent.ID {
+		t.Fatalf("expected event ID %s, got %s", publishedEvent.ID, deadEvent.ID)
+	}
+	if exchangeName != deadEvent.DeliveryInfo.Exchange {
+		t.Fatalf("expected exchange %s, got %s", exchangeName, deadEvent.DeliveryInfo.Exchange)
+	}
+	if routingKey != deadEvent.DeliveryInfo.RoutingKey {
+		t.Fatalf("expected routing key %s, got %s", routingKey, deadEvent.DeliveryInfo.RoutingKey)
+	}
+
+	goleak.VerifyNone(t)
+}
+
+func TestConsumerPermanentErrorSkipsRetries(t *testing.T) {
+	// Setup
+	queueName := uuid.NewString()
+	deadLetterQueueName := uuid.NewString()
+	exchangeName := uuid.NewString()
+	routingKey := "order.orderCreated"
+
+	type orderCreated struct {
+		ID string `json:"id"`
+	}
+
+	publishedEvent := bunnify.NewPublishableEvent(orderCreated{
+		ID: uuid.NewString(),
+	})
+
+	actualProcessing := 0
+	eventHandler := func(ctx context.Context, event bunnify.ConsumableEvent[orderCreated]) error {
+		actualProcessing++
+		return bunnify.Permanent(fmt.Errorf("error, this event will never succeed"))
+	}
+
+	var deadEvent bunnify.ConsumableEvent[orderCreated]
+	deadEventHandler := func(ctx context.Context, event bunnify.ConsumableEvent[orderCreated]) error {
+		deadEvent = event
+		return nil
+	}
+
+	// Exercise
+	connection := bunnify.NewConnection()
+	if err := connection.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	consumer := connection.NewConsumer(
+		queueName,
+		bunnify.WithQuorumQueue(),
+		bunnify.WithRetries(3),
+		bunnify.WithBindingToExchange(exchangeName),
+		bunnify.WithHandler(routingKey, eventHandler),
+		bunnify.WithDeadLetterQueue(deadLetterQueueName))
+
+	if err := consumer.Consume(); err != nil {
+		t.Fatal(err)
+	}
+
+	deadLetterConsumer := connection.NewConsumer(
+		deadLetterQueueName,
+		bunnify.WithHandler(routingKey, deadEventHandler))
+
+	if err := deadLetterConsumer.Consume(); err != nil {
+		t.Fatal(err)
+	}
+
+	publisher := connection.NewPublisher()
+
+	err := publisher.Publish(context.TODO(), exchangeName, routingKey, publishedEvent)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	if err := connection.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Assert
+	if actualProcessing != 1 {
+		t.Fatalf("expected processing 1, got %d", actualProcessing)
+	}
+
+	if publishedEvent.ID != deadEvent.ID {
+		t.Fatalf("expected event ID %s, got %s", publishedEvent.ID, deadEvent.ID)
+	}
+
+	goleak.VerifyNone(t)
+}
+
+func TestConsumerRetryableErrorRetriesAfterDuration(t *testing.T) {
+	// Setup
+	queueName := uuid.NewString()
+	exchangeName := uuid.NewString()
+	routingKey := "order.orderCreated"
+	retryAfter := 200 * time.Millisecond
+
+	type orderCreated struct {
+		ID string `json:"id"`
+	}
+
+	var processedAt []time.Time
+	eventHandler := func(ctx context.Context, event bunnify.ConsumableEvent[orderCreated]) error {
+		processedAt = append(processedAt, time.Now())
+		return bunnify.Retryable(fmt.Errorf("error, downstream unavailable"), retryAfter)
+	}
+
+	// Exercise
+	connection := bunnify.NewConnection()
+	if err := connection.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	consumer := connection.NewConsumer(
+		queueName,
+		bunnify.WithRetries(1),
+		bunnify.WithRetriesByRepublish(),
+		bunnify.WithBindingToExchange(exchangeName),
+		bunnify.WithHandler(routingKey, eventHandler))
+
+	if err := consumer.Consume(); err != nil {
+		t.Fatal(err)
+	}
+
+	publisher := connection.NewPublisher()
+
+	err := publisher.Publish(context.TODO(), exchangeName, routingKey, bunnify.NewPublishableEvent(orderCreated{
+		ID: uuid.NewString(),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(500 * time.Millisecond)
+
+	if err := connection.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Assert
+	if len(processedAt) != 2 {
+		t.Fatalf("expected processing 2, got %d", len(processedAt))
+	}
+
+	if waited := processedAt[1].Sub(processedAt[0]); waited < retryAfter {
+		t.Fatalf("expected retry to wait at least %s, waited %s", retryAfter, waited)
+	}
+
+	goleak.VerifyNone(t)
+}
@@ -0,0 +1,152 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pmorelli92/bunnify"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.uber.org/goleak"
+)
+
+func TestConsumerRun(t *testing.T) {
+	// Setup
+	queueName := uuid.NewString()
+	exchangeName := uuid.NewString()
+	routingKey := "order.orderCreated"
+
+	type orderCreated struct {
+		ID string `json:"id"`
+	}
+
+	var handled atomic.Int32
+	eventHandler := func(ctx context.Context, event bunnify.ConsumableEvent[orderCreated]) error {
+		time.Sleep(20 * time.Millisecond)
+		handled.Add(1)
+		return nil
+	}
+
+	// Exercise
+	connection := bunnify.NewConnection()
+	if err := connection.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	consumer := connection.NewConsumer(
+		queueName,
+		bunnify.WithBindingToExchange(exchangeName),
+		bunnify.WithHandler(routingKey, eventHandler))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runErr := make(chan error)
+	go func() {
+		runErr <- consumer.RunParallel(ctx)
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+
+	publisher := connection.NewPublisher()
+	event := bunnify.NewPublishableEvent(orderCreated{ID: uuid.NewString()})
+	if err := publisher.Publish(context.TODO(), exchangeName, routingKey, event); err != nil {
+		t.Fatal(err)
+	}
+
+	// Cancelled while the handler is running, so Run waits for it
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	err := <-runErr
+	handledOnReturn := handled.Load()
+
+	if err := connection.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Assert
+	if err != nil {
+		t.Fatal(err)
+	}
+	if handledOnReturn != 1 {
+		t.Fatalf("expected 1 event handled when run returns, got %d", handledOnReturn)
+	}
+	if consumer.State() != bunnify.ConsumerStateStopped {
+		t.Fatalf("expected state %s, got %s", bunnify.ConsumerStateStopped, consumer.State())
+	}
+
+	goleak.VerifyNone(t)
+}
+
+func TestConsumerRunCancelledWhileReconnecting(t *testing.T) {
+	// Setup
+	queueName := uuid.NewString()
+
+	defaultHandler := func(ctx context.Context, event bunnify.ConsumableEvent[json.RawMessage]) error {
+		return nil
+	}
+
+	// Exercise
+	connection := bunnify.NewConnection()
+	if err := connection.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	consumer := connection.NewConsumer(
+		queueName,
+		bunnify.WithReconnectBackoff(time.Minute, time.Minute),
+		bunnify.WithDefaultHandler(defaultHandler))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runErr := make(chan error)
+	go func() {
+		runErr <- consumer.Run(ctx)
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+
+	// Deleting the queue cancels the consumer, then consuming again fails and it waits for the backoff
+	amqpConnection, err := amqp.Dial("amqp://localhost:5672")
+	if err != nil {
+		t.Fatal(err)
+	}
+	channel, err := amqpConnection.Channel()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := channel.QueueDelete(queueName, false, false, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := amqpConnection.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	reconnectingState := consumer.State()
+
+	cancel()
+
+	select {
+	case err = <-runErr:
+	case <-time.After(time.Second):
+		t.Fatal("expected run to return without waiting for the backoff")
+	}
+
+	if err := connection.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Assert
+	if reconnectingState != bunnify.ConsumerStateReconnecting {
+		t.Fatalf("expected state %s, got %s", bunnify.ConsumerStateReconnecting, reconnectingState)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if consumer.State() != bunnify.ConsumerStateStopped {
+		t.Fatalf("expected state %s, got %s", bunnify.ConsumerStateStopped, consumer.State())
+	}
+
+	goleak.VerifyNone(t)
+}
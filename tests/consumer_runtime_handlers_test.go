@@ -0,0 +1,93 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pmorelli92/bunnify"
+	"go.uber.org/goleak"
+)
+
+func TestConsumerRuntimeHandlers(t *testing.T) {
+	// Setup
+	queueName := uuid.NewString()
+	exchangeName := uuid.NewString()
+	createdRoutingKey := "order.orderCreated"
+	updatedRoutingKey := "order.orderUpdated"
+
+	type orderEvent struct {
+		ID string `json:"id"`
+	}
+
+	var created, updated atomic.Int32
+	createdHandler := func(ctx context.Context, event bunnify.ConsumableEvent[orderEvent]) error {
+		created.Add(1)
+		return nil
+	}
+	updatedHandler := func(ctx context.Context, event bunnify.ConsumableEvent[orderEvent]) error {
+		updated.Add(1)
+		return nil
+	}
+
+	// Exercise
+	connection := bunnify.NewConnection()
+	if err := connection.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	consumer := connection.NewConsumer(
+		queueName,
+		bunnify.WithBindingToExchange(exchangeName),
+		bunnify.WithHandler(createdRoutingKey, createdHandler),
+		bunnify.WithDefaultHandler(func(ctx context.Context, event bunnify.ConsumableEvent[json.RawMessage]) error {
+			return nil
+		}))
+
+	if err := consumer.ConsumeParallel(); err != nil {
+		t.Fatal(err)
+	}
+
+	publisher := connection.NewPublisher()
+	publish := func(routingKey string) {
+		event := bunnify.NewPublishableEvent(orderEvent{ID: uuid.NewString()})
+		if err := publisher.Publish(context.TODO(), exchangeName, routingKey, event); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Not bound yet, so it is not routed to the queue
+	publish(updatedRoutingKey)
+
+	if err := bunnify.AddHandler(&consumer, updatedRoutingKey, updatedHandler); err != nil {
+		t.Fatal(err)
+	}
+
+	publish(updatedRoutingKey)
+	time.Sleep(50 * time.Millisecond)
+
+	if err := consumer.RemoveHandler(createdRoutingKey); err != nil {
+		t.Fatal(err)
+	}
+
+	// Not bound anymore, so it is not routed to the queue
+	publish(createdRoutingKey)
+	time.Sleep(50 * time.Millisecond)
+
+	if err := connection.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Assert
+	if updated.Load() != 1 {
+		t.Fatalf("expected 1 updated event, got %d", updated.Load())
+	}
+	if created.Load() != 0 {
+		t.Fatalf("expected 0 created events, got %d", created.Load())
+	}
+
+	goleak.VerifyNone(t)
+}
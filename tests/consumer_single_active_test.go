@@ -0,0 +1,166 @@
+package tests
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pmorelli92/bunnify"
+	"go.uber.org/goleak"
+)
+
+func TestConsumerSingleActiveConsumer(t *testing.T) {
+	// Setup
+	queueName := uuid.NewString()
+	exchangeName := uuid.NewString()
+	routingKey := uuid.NewString()
+
+	type orderCreated struct {
+		ID string `json:"id"`
+	}
+
+	mu := sync.Mutex{}
+	received := map[string]int{}
+	handlerFor := func(name string) bunnify.EventHandler[orderCreated] {
+		return func(ctx context.Context, event bunnify.ConsumableEvent[orderCreated]) error {
+			mu.Lock()
+			defer mu.Unlock()
+			received[name]++
+			return nil
+		}
+	}
+
+	exitCh := make(chan bool)
+	activeNotifications := 0
+	notificationChannel := make(chan bunnify.Notification)
+	go func() {
+		for {
+			select {
+			case n := <-notificationChannel:
+				if strings.Contains(n.Message, "single active consumer") {
+					mu.Lock()
+					activeNotifications++
+					mu.Unlock()
+				}
+			case <-exitCh:
+				return
+			}
+		}
+	}()
+
+	// Exercise
+	firstConnection := bunnify.NewConnection(bunnify.WithNotificationChannel(notificationChannel))
+	if err := firstConnection.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	secondConnection := bunnify.NewConnection(bunnify.WithNotificationChannel(notificationChannel))
+	if err := secondConnection.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, connection := range map[string]*bunnify.Connection{"first": firstConnection, "second": secondConnection} {
+		consumer := connection.NewConsumer(
+			queueName,
+			bunnify.WithSingleActiveConsumer(),
+			bunnify.WithBindingToExchange(exchangeName),
+			bunnify.WithHandler(routingKey, handlerFor(name)))
+		if err := consumer.Consume(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	publisherConnection := bunnify.NewConnection()
+	if err := publisherConnection.Start(); err != nil {
+		t.Fatal(err)
+	}
+	publisher := publisherConnection.NewPublisher()
+
+	publish := func() {
+		err := publisher.Publish(context.TODO(), exchangeName, routingKey, bunnify.NewPublishableEvent(orderCreated{
+			ID: uuid.NewString(),
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	publish()
+	publish()
+	time.Sleep(50 * time.Millisecond)
+
+	// The first consumer registered is the active one, closing it fails over to the second
+	if err := firstConnection.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	publish()
+	time.Sleep(50 * time.Millisecond)
+
+	if err := secondConnection.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := publisherConnection.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	exitCh <- true
+
+	// Assert
+	mu.Lock()
+	defer mu.Unlock()
+	if received["first"]+received["second"] != 3 {
+		t.Fatalf("expected 3 events, got %v", received)
+	}
+	if received["first"] != 2 && received["second"] != 2 {
+		t.Fatalf("expected one consumer to receive the first 2 events, got %v", received)
+	}
+	if activeNotifications != 2 {
+		t.Fatalf("expected 2 active consumer notifications, got %d", activeNotifications)
+	}
+
+	goleak.VerifyNone(t)
+}
+
+func TestConsumerExclusiveConsumer(t *testing.T) {
+	// Setup
+	queueName := uuid.NewString()
+
+	handler := func(ctx context.Context, event bunnify.ConsumableEvent[any]) error {
+		return nil
+	}
+
+	connection := bunnify.NewConnection()
+	if err := connection.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Exercise
+	consumer := connection.NewConsumer(
+		queueName,
+		bunnify.WithExclusiveConsumer(),
+		bunnify.WithHandler(uuid.NewString(), handler))
+	if err := consumer.Consume(); err != nil {
+		t.Fatal(err)
+	}
+
+	otherConsumer := connection.NewConsumer(
+		queueName,
+		bunnify.WithExclusiveConsumer(),
+		bunnify.WithHandler(uuid.NewString(), handler))
+	err := otherConsumer.Consume()
+
+	// Assert
+	if err == nil {
+		t.Fatal("expected error as the queue already has an exclusive consumer")
+	}
+
+	if err := connection.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	goleak.VerifyNone(t)
+}
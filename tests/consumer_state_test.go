@@ -0,0 +1,121 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pmorelli92/bunnify"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.uber.org/goleak"
+)
+
+func TestConsumerState(t *testing.T) {
+	defaultHandler := func(ctx context.Context, event bunnify.ConsumableEvent[json.RawMessage]) error {
+		return nil
+	}
+
+	t.Run("Consumer stops when the connection is closed", func(t *testing.T) {
+		// Setup
+		queueName := uuid.NewString()
+		changes := make(chan bunnify.StateChange, 10)
+
+		// Exercise
+		connection := bunnify.NewConnection()
+		if err := connection.Start(); err != nil {
+			t.Fatal(err)
+		}
+
+		consumer := connection.NewConsumer(
+			queueName,
+			bunnify.WithDefaultHandler(defaultHandler))
+
+		consumer.NotifyStateChange(changes)
+
+		if err := consumer.Consume(); err != nil {
+			t.Fatal(err)
+		}
+
+		consumingState := consumer.State()
+
+		if err := connection.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		time.Sleep(50 * time.Millisecond)
+
+		// Assert
+		if consumingState != bunnify.ConsumerStateConsuming {
+			t.Fatalf("expected state %s, got %s", bunnify.ConsumerStateConsuming, consumingState)
+		}
+
+		expected := []bunnify.ConsumerState{
+			bunnify.ConsumerStateStarting,
+			bunnify.ConsumerStateConsuming,
+			bunnify.ConsumerStateReconnecting,
+			bunnify.ConsumerStateStopped,
+		}
+		for _, state := range expected {
+			if change := <-changes; change.To != state {
+				t.Fatalf("expected transition to %s, got %s", state, change.To)
+			}
+		}
+
+		goleak.VerifyNone(t)
+	})
+
+	t.Run("Consumer fails when it cannot consume after the maximum attempts", func(t *testing.T) {
+		// Setup
+		queueName := uuid.NewString()
+
+		// Exercise
+		connection := bunnify.NewConnection()
+		if err := connection.Start(); err != nil {
+			t.Fatal(err)
+		}
+
+		consumer := connection.NewConsumer(
+			queueName,
+			bunnify.WithReconnectBackoff(10*time.Millisecond, 20*time.Millisecond),
+			bunnify.WithMaxReconnectAttempts(2),
+			bunnify.WithDefaultHandler(defaultHandler))
+
+		if err := consumer.Consume(); err != nil {
+			t.Fatal(err)
+		}
+
+		// Deleting the queue cancels the consumer, then consuming again fails as the queue does not exist
+		amqpConnection, err := amqp.Dial("amqp://localhost:5672")
+		if err != nil {
+			t.Fatal(err)
+		}
+		channel, err := amqpConnection.Channel()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := channel.QueueDelete(queueName, false, false, false); err != nil {
+			t.Fatal(err)
+		}
+
+		time.Sleep(200 * time.Millisecond)
+
+		if err := amqpConnection.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if err := connection.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		// Assert
+		if consumer.State() != bunnify.ConsumerStateFailed {
+			t.Fatalf("expected state %s, got %s", bunnify.ConsumerStateFailed, consumer.State())
+		}
+		if consumer.Err() == nil {
+			t.Fatal("expected the error that made the consumer fail")
+		}
+
+		goleak.VerifyNone(t)
+	})
+}
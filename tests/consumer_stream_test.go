@@ -0,0 +1,96 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pmorelli92/bunnify"
+	"go.uber.org/goleak"
+)
+
+func TestConsumerStreamResumesFromStoredOffset(t *testing.T) {
+	// Setup
+	queueName := uuid.NewString()
+	exchangeName := uuid.NewString()
+	routingKey := uuid.NewString()
+	offsetStore := bunnify.NewMemoryOffsetStore()
+
+	type orderCreated struct {
+		ID string `json:"id"`
+	}
+
+	var consumedIDs []string
+	eventHandler := func(ctx context.Context, event bunnify.ConsumableEvent[orderCreated]) error {
+		consumedIDs = append(consumedIDs, event.ID)
+		return nil
+	}
+
+	consume := func(connection *bunnify.Connection) {
+		consumer := connection.NewConsumer(
+			queueName,
+			bunnify.WithStreamQueue(),
+			bunnify.WithStreamOffset(bunnify.StreamOffsetFirst),
+			bunnify.WithOffsetStore(offsetStore),
+			bunnify.WithBindingToExchange(exchangeName),
+			bunnify.WithHandler(routingKey, eventHandler))
+		if err := consumer.Consume(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	publish := func(publisher *bunnify.Publisher) bunnify.PublishableEvent {
+		event := bunnify.NewPublishableEvent(orderCreated{ID: uuid.NewString()})
+		if err := publisher.Publish(context.TODO(), exchangeName, routingKey, event); err != nil {
+			t.Fatal(err)
+		}
+		return event
+	}
+
+	// Exercise
+	connection := bunnify.NewConnection()
+	if err := connection.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	consume(connection)
+	publisher := connection.NewPublisher()
+	firstEvent := publish(publisher)
+	secondEvent := publish(publisher)
+
+	time.Sleep(100 * time.Millisecond)
+
+	if err := connection.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// A new connection should only receive the events not handled yet
+	connection = bunnify.NewConnection()
+	if err := connection.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	publisher = connection.NewPublisher()
+	thirdEvent := publish(publisher)
+	consume(connection)
+
+	time.Sleep(100 * time.Millisecond)
+
+	if err := connection.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Assert
+	expectedIDs := []string{firstEvent.ID, secondEvent.ID, thirdEvent.ID}
+	if len(consumedIDs) != len(expectedIDs) {
+		t.Fatalf("expected %d events, got %d", len(expectedIDs), len(consumedIDs))
+	}
+	for i := range expectedIDs {
+		if expectedIDs[i] != consumedIDs[i] {
+			t.Fatalf("expected event %d to be %s, got %s", i, expectedIDs[i], consumedIDs[i])
+		}
+	}
+
+	goleak.VerifyNone(t)
+}
@@ -0,0 +1,85 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pmorelli92/bunnify"
+	"go.uber.org/goleak"
+)
+
+func TestConsumerTopicExchange(t *testing.T) {
+	// Setup
+	queueName := uuid.NewString()
+	exchangeName := uuid.NewString()
+
+	type orderEvent struct {
+		ID string `json:"id"`
+	}
+
+	var exactKeys, wildcardKeys, multiWordKeys []string
+	exactHandler := func(ctx context.Context, event bunnify.ConsumableEvent[orderEvent]) error {
+		exactKeys = append(exactKeys, event.DeliveryInfo.RoutingKey)
+		return nil
+	}
+	wildcardHandler := func(ctx context.Context, event bunnify.ConsumableEvent[orderEvent]) error {
+		wildcardKeys = append(wildcardKeys, event.DeliveryInfo.RoutingKey)
+		return nil
+	}
+	multiWordHandler := func(ctx context.Context, event bunnify.ConsumableEvent[orderEvent]) error {
+		multiWordKeys = append(multiWordKeys, event.DeliveryInfo.RoutingKey)
+		return nil
+	}
+
+	// Exercise
+	connection := bunnify.NewConnection()
+	if err := connection.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	consumer := connection.NewConsumer(
+		queueName,
+		bunnify.WithBindingToExchange(exchangeName),
+		bunnify.WithExchangeKind(bunnify.ExchangeKindTopic),
+		bunnify.WithHandler("order.created", exactHandler),
+		bunnify.WithHandler("order.*", wildcardHandler),
+		bunnify.WithHandler("audit.#", multiWordHandler))
+
+	if err := consumer.Consume(); err != nil {
+		t.Fatal(err)
+	}
+
+	publisher := connection.NewPublisher()
+
+	for _, routingKey := range []string{"order.created", "order.updated", "audit.order.created", "other.created"} {
+		err := publisher.Publish(
+			context.TODO(),
+			exchangeName,
+			routingKey,
+			bunnify.NewPublishableEvent(orderEvent{ID: uuid.NewString()}))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	if err := connection.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Assert
+	if len(exactKeys) != 1 || exactKeys[0] != "order.created" {
+		t.Fatalf("expected exact handler to receive order.created, got %v", exactKeys)
+	}
+	if len(wildcardKeys) != 1 || wildcardKeys[0] != "order.updated" {
+		t.Fatalf("expected wildcard handler to receive order.updated, got %v", wildcardKeys)
+	}
+	if len(multiWordKeys) != 1 || multiWordKeys[0] != "audit.order.created" {
+		t.Fatalf("expected multi word handler to receive audit.order.created, got %v", multiWordKeys)
+	}
+
+	goleak.VerifyNone(t)
+}
@@ -0,0 +1,100 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pmorelli92/bunnify"
+	"go.uber.org/goleak"
+)
+
+func TestDeadLetterReceivesFailureDetails(t *testing.T) {
+	// Setup
+	queueName := uuid.NewString()
+	deadLetterQueueName := uuid.NewString()
+	exchangeName := uuid.NewString()
+	routingKey := "order.orderCreated"
+
+	type orderCreated struct {
+		ID string `json:"id"`
+	}
+
+	publishedEvent := bunnify.NewPublishableEvent(orderCreated{ID: uuid.NewString()})
+
+	eventHandler := func(ctx context.Context, event bunnify.ConsumableEvent[orderCreated]) error {
+		return fmt.Errorf("error, this event will go to dead-letter")
+	}
+
+	var deadEvent bunnify.ConsumableEvent[orderCreated]
+	deadEventHandler := func(ctx context.Context, event bunnify.ConsumableEvent[orderCreated]) error {
+		deadEvent = event
+		return nil
+	}
+
+	// Exercise
+	connection := bunnify.NewConnection()
+	if err := connection.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	consumer := connection.NewConsumer(
+		queueName,
+		bunnify.WithRetries(1),
+		bunnify.WithRetriesByRepublish(),
+		bunnify.WithBindingToExchange(exchangeName),
+		bunnify.WithHandler(routingKey, eventHandler),
+		bunnify.WithDeadLetterQueue(deadLetterQueueName),
+		bunnify.WithFailureDetails())
+
+	if err := consumer.Consume(); err != nil {
+		t.Fatal(err)
+	}
+
+	deadLetterConsumer := connection.NewConsumer(
+		deadLetterQueueName,
+		bunnify.WithHandler(routingKey, deadEventHandler))
+
+	if err := deadLetterConsumer.Consume(); err != nil {
+		t.Fatal(err)
+	}
+
+	publisher := connection.NewPublisher()
+
+	err := publisher.Publish(context.TODO(), exchangeName, routingKey, publishedEvent)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	if err := connection.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Assert
+	if publishedEvent.ID != deadEvent.ID {
+		t.Fatalf("expected event ID %s, got %s", publishedEvent.ID, deadEvent.ID)
+	}
+	if queueName != deadEvent.DeliveryInfo.Queue {
+		t.Fatalf("expected queue %s, got %s", queueName, deadEvent.DeliveryInfo.Queue)
+	}
+
+	headers := deadEvent.DeliveryInfo.Headers
+	if headers["x-bunnify-error-message"] != "error, this event will go to dead-letter" {
+		t.Fatalf("unexpected error message %v", headers["x-bunnify-error-message"])
+	}
+	if headers["x-bunnify-handler-routing-key"] != routingKey {
+		t.Fatalf("unexpected handler routing key %v", headers["x-bunnify-handler-routing-key"])
+	}
+	if headers["x-bunnify-attempts"] != int64(2) {
+		t.Fatalf("expected 2 attempts, got %v", headers["x-bunnify-attempts"])
+	}
+	if _, ok := headers["x-bunnify-failed-at"].(time.Time); !ok {
+		t.Fatalf("expected failure time, got %v", headers["x-bunnify-failed-at"])
+	}
+
+	goleak.VerifyNone(t)
+}
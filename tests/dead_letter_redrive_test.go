@@ -0,0 +1,188 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pmorelli92/bunnify"
+	"go.uber.org/goleak"
+)
+
+func TestDeadLetterRedrive(t *testing.T) {
+	// Setup
+	queueName := uuid.NewString()
+	deadLetterQueueName := uuid.NewString()
+	exchangeName := uuid.NewString()
+	redrivenKey := "order.orderCreated"
+	skippedKey := "order.orderUpdated"
+
+	type orderEvent struct {
+		ID string `json:"id"`
+	}
+
+	failing := true
+	var handled []string
+	eventHandler := func(ctx context.Context, event bunnify.ConsumableEvent[orderEvent]) error {
+		if failing {
+			return fmt.Errorf("error, this event will go to dead-letter")
+		}
+		handled = append(handled, event.DeliveryInfo.RoutingKey)
+		return nil
+	}
+
+	connection := bunnify.NewConnection()
+	if err := connection.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	consumer := connection.NewConsumer(
+		queueName,
+		bunnify.WithBindingToExchange(exchangeName),
+		bunnify.WithHandler(redrivenKey, eventHandler),
+		bunnify.WithHandler(skippedKey, eventHandler),
+		bunnify.WithDeadLetterQueue(deadLetterQueueName))
+
+	if err := consumer.Consume(); err != nil {
+		t.Fatal(err)
+	}
+
+	publisher := connection.NewPublisher()
+	for _, routingKey := range []string{redrivenKey, redrivenKey, skippedKey} {
+		err := publisher.Publish(context.TODO(), exchangeName, routingKey, bunnify.NewPublishableEvent(orderEvent{
+			ID: uuid.NewString(),
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	failing = false
+
+	// Exercise dry run
+	dryRun, err := connection.Redrive(
+		context.TODO(),
+		deadLetterQueueName,
+		bunnify.WithRedriveRoutingKeys(redrivenKey),
+		bunnify.WithRedriveDryRun())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Exercise redrive
+	result, err := connection.Redrive(
+		context.TODO(),
+		deadLetterQueueName,
+		bunnify.WithRedriveRoutingKeys(redrivenKey),
+		bunnify.WithRedriveRate(100))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	if err := connection.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Assert
+	if dryRun.Redriven != 2 || dryRun.Skipped != 1 {
+		t.Fatalf("expected dry run to redrive 2 and skip 1, got %+v", dryRun)
+	}
+	if result.Redriven != 2 || result.Skipped != 1 || result.Failed != 0 {
+		t.Fatalf("expected to redrive 2 and skip 1, got %+v", result)
+	}
+	if len(handled) != 2 || handled[0] != redrivenKey || handled[1] != redrivenKey {
+		t.Fatalf("expected 2 redriven events to be handled, got %v", handled)
+	}
+
+	goleak.VerifyNone(t)
+}
+
+func TestDeadLetterRedriveWithFailureDetails(t *testing.T) {
+	// Setup
+	queueName := uuid.NewString()
+	deadLetterQueueName := uuid.NewString()
+	exchangeName := uuid.NewString()
+	routingKey := "order.orderCreated"
+
+	type orderEvent struct {
+		ID string `json:"id"`
+	}
+
+	failing := true
+	handled := 0
+	eventHandler := func(ctx context.Context, event bunnify.ConsumableEvent[orderEvent]) error {
+		if failing {
+			return fmt.Errorf("error, this event will go to dead-letter")
+		}
+		handled++
+		return nil
+	}
+
+	connection := bunnify.NewConnection()
+	if err := connection.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	consumer := connection.NewConsumer(
+		queueName,
+		bunnify.WithBindingToExchange(exchangeName),
+		bunnify.WithHandler(routingKey, eventHandler),
+		bunnify.WithDeadLetterQueue(deadLetterQueueName),
+		bunnify.WithFailureDetails())
+
+	if err := consumer.Consume(); err != nil {
+		t.Fatal(err)
+	}
+
+	publisher := connection.NewPublisher()
+	err := publisher.Publish(context.TODO(), exchangeName, routingKey, bunnify.NewPublishableEvent(orderEvent{
+		ID: uuid.NewString(),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	failing = false
+
+	// Exercise, the event was published to the dead letter exchange by bunnify so it has no x-death
+	tooRecent, err := connection.Redrive(
+		context.TODO(),
+		deadLetterQueueName,
+		bunnify.WithRedriveMinAge(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := connection.Redrive(
+		context.TODO(),
+		deadLetterQueueName,
+		bunnify.WithRedriveMaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	if err := connection.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Assert
+	if tooRecent.Redriven != 0 || tooRecent.Skipped != 1 {
+		t.Fatalf("expected to skip the recent event, got %+v", tooRecent)
+	}
+	if result.Redriven != 1 || result.Skipped != 0 {
+		t.Fatalf("expected to redrive the event, got %+v", result)
+	}
+	if handled != 1 {
+		t.Fatalf("expected the redriven event to be handled, got %d", handled)
+	}
+
+	goleak.VerifyNone(t)
+}
@@ -0,0 +1,111 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pmorelli92/bunnify"
+	"go.uber.org/goleak"
+)
+
+func TestHealthChecker(t *testing.T) {
+	// Setup
+	queueName := uuid.NewString()
+	exchangeName := uuid.NewString()
+	routingKey := uuid.NewString()
+
+	type orderCreated struct {
+		ID string `json:"id"`
+	}
+
+	eventHandler := func(ctx context.Context, event bunnify.ConsumableEvent[orderCreated]) error {
+		return fmt.Errorf("order %s could not be handled", event.Payload.ID)
+	}
+
+	getReport := func(handler http.Handler, path string) (int, bunnify.HealthReport) {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+
+		var report bunnify.HealthReport
+		if err := json.NewDecoder(recorder.Body).Decode(&report); err != nil {
+			t.Fatal(err)
+		}
+		return recorder.Code, report
+	}
+
+	// Exercise
+	connection := bunnify.NewConnection()
+	if err := connection.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	consumer := connection.NewConsumer(
+		queueName,
+		bunnify.WithQuorumQueue(),
+		bunnify.WithBindingToExchange(exchangeName),
+		bunnify.WithHandler(routingKey, eventHandler))
+
+	publisher := connection.NewPublisher()
+
+	checker := connection.NewHealthChecker(
+		bunnify.WithHealthConsumers(&consumer),
+		bunnify.WithHealthPublishers(publisher))
+	handler := checker.Handler()
+
+	idleStatus, idleReport := getReport(handler, "/ready")
+
+	if err := consumer.Consume(); err != nil {
+		t.Fatal(err)
+	}
+
+	err := publisher.Publish(
+		context.TODO(),
+		exchangeName,
+		routingKey,
+		bunnify.NewPublishableEvent(orderCreated{ID: uuid.NewString()}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	readyStatus, readyReport := getReport(handler, "/ready")
+
+	if err := connection.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	closedStatus, closedReport := getReport(handler, "/ready")
+
+	// Assert
+	if idleStatus != http.StatusServiceUnavailable || idleReport.Consumers[0].State != bunnify.ConsumerStateIdle {
+		t.Fatalf("expected idle consumer not to be ready, got %d %+v", idleStatus, idleReport)
+	}
+
+	if readyStatus != http.StatusOK || !readyReport.Connected {
+		t.Fatalf("expected to be ready, got %d %+v", readyStatus, readyReport)
+	}
+	if readyReport.Consumers[0].LastHandlerError == "" {
+		t.Fatal("expected the last handler error to be reported")
+	}
+	if readyReport.Publishers[0].Channel != bunnify.ChannelStateOpen {
+		t.Fatalf("expected publisher channel %s, got %s", bunnify.ChannelStateOpen, readyReport.Publishers[0].Channel)
+	}
+
+	if closedStatus != http.StatusServiceUnavailable || closedReport.Connected {
+		t.Fatalf("expected closed connection not to be ready, got %d %+v", closedStatus, closedReport)
+	}
+	if closedReport.Consumers[0].State != bunnify.ConsumerStateStopped {
+		t.Fatalf("expected state %s, got %s", bunnify.ConsumerStateStopped, closedReport.Consumers[0].State)
+	}
+
+	goleak.VerifyNone(t)
+}
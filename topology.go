@@ -0,0 +1,248 @@
+package bunnify
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// Topology describes the exchanges, queues and bindings that the application relies on.
+// When supplied with WithTopology, it is declared every time the connection is established,
+// so the entities are created again if the server lost them.
+type Topology struct {
+	Exchanges        []ExchangeDefinition
+	Queues           []QueueDefinition
+	QueueBindings    []QueueBinding
+	ExchangeBindings []ExchangeBinding
+}
+
+// ExchangeDefinition describes an exchange. If AlternateExchange is supplied,
+// the events that cannot be routed are sent to that exchange.
+type ExchangeDefinition struct {
+	Name              string
+	Kind              ExchangeKind
+	Durable           bool
+	AutoDelete        bool
+	Internal          bool
+	AlternateExchange string
+	Arguments         map[string]any
+}
+
+// QueueDefinition describes a queue. The arguments such as the queue
+// type or the dead letter exchange are supplied as Arguments.
+type QueueDefinition struct {
+	Name       string
+	Durable    bool
+	AutoDelete bool
+	Exclusive  bool
+	Arguments  map[string]any
+}
+
+// QueueBinding binds a queue to an exchange with the given routing key.
+// For headers exchanges, the headers to match are supplied as Arguments.
+type QueueBinding struct {
+	Queue      string
+	Exchange   string
+	RoutingKey string
+	Arguments  map[string]any
+}
+
+// ExchangeBinding binds the destination exchange to the source exchange with the given routing key.
+type ExchangeBinding struct {
+	Destination string
+	Source      string
+	RoutingKey  string
+	Arguments   map[string]any
+}
+
+// TopologyError is returned when some of the entities of the topology could not be declared.
+// The errors are indexed by the entity, so the rest of the topology is declared anyway.
+type TopologyError struct {
+	Errors map[string]error
+}
+
+func (e *TopologyError) Error() string {
+	entities := make([]string, 0, len(e.Errors))
+	for entity := range e.Errors {
+		entities = append(entities, entity)
+	}
+	sort.Strings(entities)
+
+	msgs := make([]string, 0, len(entities))
+	for _, entity := range entities {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", entity, e.Errors[entity]))
+	}
+	return fmt.Sprintf("topology failed for %d entities: %s", len(entities), strings.Join(msgs, "; "))
+}
+
+// topologyStep declares or verifies a single entity of the topology. The expected description
+// is used to report mismatches. Bindings cannot be verified with passive declarations, so they
+// do not have a verify function, only an inspect one that reads them from the management API.
+type topologyStep struct {
+	entity   string
+	expected string
+	declare  func(channel *amqp.Channel) error
+	verify   func(channel *amqp.Channel) error
+	inspect  func(api *managementAPI) ([]TopologyMismatch, error)
+}
+
+// steps returns the declarations in order: exchanges and queues first, then the bindings between them.
+func (t Topology) steps() []topologyStep {
+	steps := make([]topologyStep, 0)
+
+	for _, e := range t.Exchanges {
+		steps = append(steps, e.step())
+	}
+
+	for _, q := range t.Queues {
+		steps = append(steps, q.step())
+	}
+
+	for _, b := range t.ExchangeBindings {
+		entity := fmt.Sprintf("exchange binding %s to %s with %s", b.Destination, b.Source, b.RoutingKey)
+		steps = append(steps, topologyStep{
+			entity: entity,
+			declare: func(channel *amqp.Channel) error {
+				return channel.ExchangeBind(b.Destination, b.RoutingKey, b.Source, false, amqp.Table(b.Arguments))
+			},
+			inspect: func(api *managementAPI) ([]TopologyMismatch, error) {
+				return inspectBinding(api, entity, b.Source, "e", b.Destination, b.RoutingKey, b.Arguments)
+			},
+		})
+	}
+
+	for _, b := range t.QueueBindings {
+		entity := fmt.Sprintf("queue binding %s to %s with %s", b.Queue, b.Exchange, b.RoutingKey)
+		steps = append(steps, topologyStep{
+			entity: entity,
+			declare: func(channel *amqp.Channel) error {
+				return channel.QueueBind(b.Queue, b.RoutingKey, b.Exchange, false, amqp.Table(b.Arguments))
+			},
+			inspect: func(api *managementAPI) ([]TopologyMismatch, error) {
+				return inspectBinding(api, entity, b.Exchange, "q", b.Queue, b.RoutingKey, b.Arguments)
+			},
+		})
+	}
+
+	return steps
+}
+
+func (e ExchangeDefinition) step() topologyStep {
+	return topologyStep{
+		entity:   fmt.Sprintf("exchange %s", e.Name),
+		expected: describeEntity(e.Durable, fmt.Sprintf("%s exchange", e.kind()), e.arguments()),
+		declare: func(channel *amqp.Channel) error {
+			return channel.ExchangeDeclare(e.Name, string(e.kind()), e.Durable, e.AutoDelete, e.Internal, false, e.arguments())
+		},
+		verify: func(channel *amqp.Channel) error {
+			return channel.ExchangeDeclarePassive(e.Name, string(e.kind()), e.Durable, e.AutoDelete, e.Internal, false, e.arguments())
+		},
+		inspect: e.inspect,
+	}
+}
+
+func (q QueueDefinition) step() topologyStep {
+	return topologyStep{
+		entity:   fmt.Sprintf("queue %s", q.Name),
+		expected: describeEntity(q.Durable, "queue", amqp.Table(q.Arguments)),
+		declare: func(channel *amqp.Channel) error {
+			_, err := channel.QueueDeclare(q.Name, q.Durable, q.AutoDelete, q.Exclusive, false, amqp.Table(q.Arguments))
+			return err
+		},
+		verify: func(channel *amqp.Channel) error {
+			_, err := channel.QueueDeclarePassive(q.Name, q.Durable, q.AutoDelete, q.Exclusive, false, amqp.Table(q.Arguments))
+			return err
+		},
+		inspect: q.inspect,
+	}
+}
+
+// kind returns the kind of the exchange, direct if not specified.
+func (e ExchangeDefinition) kind() ExchangeKind {
+	if e.Kind == "" {
+		return ExchangeKindDirect
+	}
+	return e.Kind
+}
+
+// arguments returns the arguments of the exchange including the alternate exchange.
+func (e ExchangeDefinition) arguments() amqp.Table {
+	args := amqp.Table{}
+	for k, v := range e.Arguments {
+		args[k] = v
+	}
+	if e.AlternateExchange != "" {
+		args["alternate-exchange"] = e.AlternateExchange
+	}
+	return args
+}
+
+// applyTopology declares the topology supplied with WithTopology, if any. Declarations are
+// idempotent, so it is safe to apply it on every connection. Each failure is sent to the notification
+// channel. When WithPassiveTopology is supplied, the topology is verified instead and the mismatches
+// are sent to the notification channel as well.
+func (c *Connection) applyTopology(conn *amqp.Connection) error {
+	if c.options.topology == nil {
+		return nil
+	}
+
+	steps := c.options.topology.steps()
+	newChannel := conn.Channel
+	if c.options.passiveTopology {
+		err := verifyTopology(steps, newChannel, c.managementAPI)
+		if err != nil {
+			notifyTopologyMismatch(c.options.notificationChannel, err)
+		}
+		return err
+	}
+
+	failed := make(map[string]error)
+	runTopology(steps, false, newChannel, func(step topologyStep, err error) {
+		failed[step.entity] = err
+		notifyTopologyFailed(c.options.notificationChannel, step.entity, err)
+	})
+
+	if len(failed) > 0 {
+		return &TopologyError{Errors: failed}
+	}
+	return nil
+}
+
+// runTopology declares or verifies the entities in order. A failure closes the channel,
+// so a new one is opened for the next entity. Steps that cannot be verified are skipped.
+func runTopology(
+	steps []topologyStep,
+	passive bool,
+	newChannel func() (*amqp.Channel, error),
+	onFailure func(step topologyStep, err error)) {
+
+	var channel *amqp.Channel
+	defer func() {
+		if channel != nil && !channel.IsClosed() {
+			channel.Close()
+		}
+	}()
+
+	for _, step := range steps {
+		run := step.declare
+		if passive {
+			run = step.verify
+		}
+		if run == nil {
+			continue
+		}
+
+		var err error
+		if channel == nil || channel.IsClosed() {
+			channel, err = newChannel()
+		}
+		if err == nil {
+			err = run(channel)
+		}
+		if err != nil {
+			onFailure(step, err)
+		}
+	}
+}
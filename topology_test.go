@@ -0,0 +1,76 @@
+package bunnify
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTopologySteps(t *testing.T) {
+	// Setup
+	topology := Topology{
+		QueueBindings:    []QueueBinding{{Queue: "orders", Exchange: "events", RoutingKey: "order.*"}},
+		ExchangeBindings: []ExchangeBinding{{Destination: "events", Source: "upstream", RoutingKey: "#"}},
+		Queues:           []QueueDefinition{{Name: "orders", Durable: true}},
+		Exchanges:        []ExchangeDefinition{{Name: "events", Kind: ExchangeKindTopic}},
+	}
+
+	// Exercise
+	steps := topology.steps()
+
+	// Assert
+	expected := []string{
+		"exchange events",
+		"queue orders",
+		"exchange binding events to upstream with #",
+		"queue binding orders to events with order.*",
+	}
+	if len(steps) != len(expected) {
+		t.Fatalf("expected %d steps, got %d", len(expected), len(steps))
+	}
+	for i, step := range steps {
+		if step.entity != expected[i] {
+			t.Fatalf("expected step %d to be %s, got %s", i, expected[i], step.entity)
+		}
+	}
+}
+
+func TestExchangeDefinition(t *testing.T) {
+	// Setup
+	exchange := ExchangeDefinition{
+		Name:              "events",
+		AlternateExchange: "unrouted",
+		Arguments:         map[string]any{"x-custom": "value"},
+	}
+
+	// Exercise
+	kind := exchange.kind()
+	args := exchange.arguments()
+
+	// Assert
+	if kind != ExchangeKindDirect {
+		t.Fatalf("expected direct exchange, got %s", kind)
+	}
+	if args["alternate-exchange"] != "unrouted" || args["x-custom"] != "value" {
+		t.Fatalf("unexpected arguments %v", args)
+	}
+	if _, ok := exchange.Arguments["alternate-exchange"]; ok {
+		t.Fatal("expected arguments of the definition not to be modified")
+	}
+}
+
+func TestTopologyError(t *testing.T) {
+	// Setup
+	err := &TopologyError{Errors: map[string]error{
+		"queue orders":    errors.New("access refused"),
+		"exchange events": errors.New("precondition failed"),
+	}}
+
+	// Exercise
+	msg := err.Error()
+
+	// Assert
+	expected := "topology failed for 2 entities: exchange events: precondition failed; queue orders: access refused"
+	if msg != expected {
+		t.Fatalf("expected %s, got %s", expected, msg)
+	}
+}
@@ -2,12 +2,20 @@ package bunnify
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	amqp "github.com/rabbitmq/amqp091-go"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
+const tracerName = "github.com/pmorelli92/bunnify"
+
 // inject the span context to amqp table
 func injectToHeaders(ctx context.Context) amqp.Table {
 	carrier := propagation.MapCarrier{}
@@ -32,3 +40,111 @@ func extractToContext(headers amqp.Table) context.Context {
 
 	return otel.GetTextMapPropagator().Extract(context.TODO(), carrier)
 }
+
+// tracer returns the bunnify tracer of the provider, or of the global one if none was supplied.
+func tracer(provider trace.TracerProvider) trace.Tracer {
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+	return provider.Tracer(tracerName)
+}
+
+// destinationName returns the exchange as the messaging destination. The
+// default exchange has no name, so it is reported as amq.default.
+func destinationName(exchange string) string {
+	if exchange == "" {
+		return "amq.default"
+	}
+	return exchange
+}
+
+// startPublishSpan starts a producer span for publishing an event to the exchange.
+func startPublishSpan(
+	ctx context.Context,
+	provider trace.TracerProvider,
+	exchange, routingKey string,
+	event PublishableEvent) (context.Context, trace.Span) {
+
+	destination := destinationName(exchange)
+	return tracer(provider).Start(ctx, fmt.Sprintf("publish %s", destination),
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystemRabbitMQ,
+			semconv.MessagingOperationName("publish"),
+			semconv.MessagingOperationTypeSend,
+			semconv.MessagingDestinationName(destination),
+			semconv.MessagingRabbitMQDestinationRoutingKey(routingKey),
+			semconv.MessagingMessageID(event.ID),
+			semconv.MessagingMessageConversationID(event.CorrelationID),
+		))
+}
+
+// startProcessSpan starts a consumer span for handling an event, as a child of the span it was published with.
+func (c *Consumer) startProcessSpan(
+	delivery amqp.Delivery,
+	deliveryInfo DeliveryInfo,
+	event unmarshalEvent) (context.Context, trace.Span) {
+
+	ctx := extractToContext(delivery.Headers)
+	return tracer(c.options.tracerProvider).Start(ctx, fmt.Sprintf("process %s", c.queueName),
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			processAttributes(c.queueName, deliveryInfo)...,
+		),
+		trace.WithAttributes(
+			semconv.MessagingMessageID(event.ID),
+			semconv.MessagingMessageConversationID(event.CorrelationID),
+			semconv.MessagingRabbitMQMessageDeliveryTag(int(delivery.DeliveryTag)),
+		))
+}
+
+// startBatchSpan starts a consumer span for handling a batch. It is a child of
+// the span the first event was published with and links to the span of every event.
+func (c *Consumer) startBatchSpan(items []batchItem) (context.Context, trace.Span) {
+	links := make([]trace.Link, 0, len(items))
+	for _, item := range items {
+		spanContext := trace.SpanContextFromContext(extractToContext(item.delivery.Headers))
+		if spanContext.IsValid() {
+			links = append(links, trace.Link{SpanContext: spanContext})
+		}
+	}
+
+	ctx := extractToContext(items[0].delivery.Headers)
+	return tracer(c.options.tracerProvider).Start(ctx, fmt.Sprintf("process %s", c.queueName),
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithLinks(links...),
+		trace.WithAttributes(
+			processAttributes(c.queueName, items[0].deliveryInfo)...,
+		),
+		trace.WithAttributes(
+			semconv.MessagingBatchMessageCount(len(items)),
+		))
+}
+
+func processAttributes(queueName string, deliveryInfo DeliveryInfo) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		semconv.MessagingSystemRabbitMQ,
+		semconv.MessagingOperationName("process"),
+		semconv.MessagingOperationTypeProcess,
+		semconv.MessagingDestinationName(destinationName(deliveryInfo.Exchange)),
+		semconv.MessagingDestinationSubscriptionName(queueName),
+		semconv.MessagingRabbitMQDestinationRoutingKey(deliveryInfo.RoutingKey),
+	}
+}
+
+// endSpan sets the status of the span from the error and ends it. Dispositions are
+// chosen by the handler, so they are recorded as an attribute instead of as an error.
+func endSpan(span trace.Span, err error) {
+	defer span.End()
+
+	var disposition *DispositionError
+	switch {
+	case err == nil:
+	case errors.As(err, &disposition):
+		span.SetAttributes(attribute.String("bunnify.disposition", string(disposition.Disposition)))
+	default:
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		span.SetAttributes(semconv.ErrorType(err))
+	}
+}
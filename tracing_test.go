@@ -0,0 +1,162 @@
+package bunnify
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	tracesdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracing(t *testing.T) {
+	newProvider := func() (*tracesdk.TracerProvider, *tracetest.SpanRecorder) {
+		recorder := tracetest.NewSpanRecorder()
+		return tracesdk.NewTracerProvider(tracesdk.WithSpanProcessor(recorder)), recorder
+	}
+
+	attributes := func(span tracesdk.ReadOnlySpan) map[attribute.Key]attribute.Value {
+		values := make(map[attribute.Key]attribute.Value)
+		for _, kv := range span.Attributes() {
+			values[kv.Key] = kv.Value
+		}
+		return values
+	}
+
+	t.Run("Publish span follows the messaging conventions", func(t *testing.T) {
+		// Setup
+		provider, recorder := newProvider()
+		event := NewPublishableEvent(struct{}{}, WithEventID("event-id"), WithCorrelationID("correlation-id"))
+
+		// Exercise
+		_, span := startPublishSpan(context.TODO(), provider, "", "routing-key", event)
+		endSpan(span, nil)
+
+		// Assert
+		spans := recorder.Ended()
+		if len(spans) != 1 {
+			t.Fatalf("expected 1 span, got %d", len(spans))
+		}
+		if spans[0].Name() != "publish amq.default" || spans[0].SpanKind() != trace.SpanKindProducer {
+			t.Fatalf("unexpected span %s of kind %s", spans[0].Name(), spans[0].SpanKind())
+		}
+		expected := map[attribute.Key]string{
+			"messaging.system":                           "rabbitmq",
+			"messaging.operation.name":                   "publish",
+			"messaging.operation.type":                   "send",
+			"messaging.destination.name":                 "amq.default",
+			"messaging.rabbitmq.destination.routing_key": "routing-key",
+			"messaging.message.id":                       "event-id",
+			"messaging.message.conversation_id":          "correlation-id",
+		}
+		actual := attributes(spans[0])
+		for k, v := range expected {
+			if actual[k].AsString() != v {
+				t.Fatalf("expected %s to be %s, got %s", k, v, actual[k].AsString())
+			}
+		}
+		if spans[0].Status().Code != codes.Unset {
+			t.Fatalf("expected unset status, got %s", spans[0].Status().Code)
+		}
+	})
+
+	t.Run("Process span is a child of the published span", func(t *testing.T) {
+		// Setup
+		propagator := otel.GetTextMapPropagator()
+		otel.SetTextMapPropagator(propagation.TraceContext{})
+		defer otel.SetTextMapPropagator(propagator)
+
+		provider, recorder := newProvider()
+		consumer := Consumer{&consumerCore{queueName: "queue", options: consumerOption{tracerProvider: provider}}}
+
+		publishCtx, publishSpan := startPublishSpan(context.TODO(), provider, "exchange", "routing-key", NewPublishableEvent(struct{}{}))
+		delivery := amqp.Delivery{Headers: injectToHeaders(publishCtx), DeliveryTag: 7}
+		deliveryInfo := DeliveryInfo{Queue: "queue", Exchange: "exchange", RoutingKey: "routing-key"}
+
+		// Exercise
+		_, span := consumer.startProcessSpan(delivery, deliveryInfo, unmarshalEvent{Metadata: Metadata{ID: "event-id"}})
+		endSpan(span, nil)
+
+		// Assert
+		spans := recorder.Ended()
+		if len(spans) != 1 {
+			t.Fatalf("expected 1 span, got %d", len(spans))
+		}
+		if spans[0].Name() != "process queue" || spans[0].SpanKind() != trace.SpanKindConsumer {
+			t.Fatalf("unexpected span %s of kind %s", spans[0].Name(), spans[0].SpanKind())
+		}
+		if spans[0].Parent().SpanID() != publishSpan.SpanContext().SpanID() {
+			t.Fatal("expected process span to be a child of the publish span")
+		}
+		actual := attributes(spans[0])
+		if actual["messaging.destination.subscription.name"].AsString() != "queue" ||
+			actual["messaging.message.id"].AsString() != "event-id" ||
+			actual["messaging.rabbitmq.message.delivery_tag"].AsInt64() != 7 {
+			t.Fatalf("unexpected attributes %v", actual)
+		}
+	})
+
+	t.Run("Batch span links to every event", func(t *testing.T) {
+		// Setup
+		propagator := otel.GetTextMapPropagator()
+		otel.SetTextMapPropagator(propagation.TraceContext{})
+		defer otel.SetTextMapPropagator(propagator)
+
+		provider, recorder := newProvider()
+		consumer := Consumer{&consumerCore{queueName: "queue", options: consumerOption{tracerProvider: provider}}}
+
+		items := make([]batchItem, 0)
+		for range 3 {
+			ctx, span := startPublishSpan(context.TODO(), provider, "exchange", "routing-key", NewPublishableEvent(struct{}{}))
+			items = append(items, batchItem{delivery: amqp.Delivery{Headers: injectToHeaders(ctx)}})
+			span.End()
+		}
+
+		// Exercise
+		_, span := consumer.startBatchSpan(items)
+		endSpan(span, nil)
+
+		// Assert
+		spans := recorder.Ended()
+		batchSpan := spans[len(spans)-1]
+		if len(batchSpan.Links()) != 3 {
+			t.Fatalf("expected 3 links, got %d", len(batchSpan.Links()))
+		}
+		if attributes(batchSpan)["messaging.batch.message_count"].AsInt64() != 3 {
+			t.Fatal("expected the batch message count")
+		}
+	})
+
+	t.Run("Handler errors set the span status", func(t *testing.T) {
+		// Setup
+		provider, recorder := newProvider()
+		consumer := Consumer{&consumerCore{queueName: "queue", options: consumerOption{tracerProvider: provider}}}
+
+		// Exercise
+		_, failed := consumer.startProcessSpan(amqp.Delivery{}, DeliveryInfo{}, unmarshalEvent{})
+		endSpan(failed, errors.New("handler failed"))
+		_, disposed := consumer.startProcessSpan(amqp.Delivery{}, DeliveryInfo{}, unmarshalEvent{})
+		endSpan(disposed, Requeue())
+
+		// Assert
+		spans := recorder.Ended()
+		if spans[0].Status().Code != codes.Error || spans[0].Status().Description != "handler failed" {
+			t.Fatalf("expected error status, got %+v", spans[0].Status())
+		}
+		if len(spans[0].Events()) != 1 {
+			t.Fatalf("expected the error to be recorded, got %d events", len(spans[0].Events()))
+		}
+		if spans[1].Status().Code != codes.Unset {
+			t.Fatalf("expected disposition not to be an error, got %+v", spans[1].Status())
+		}
+		if attributes(spans[1])["bunnify.disposition"].AsString() != string(DispositionRequeue) {
+			t.Fatal("expected the disposition attribute")
+		}
+	})
+}